@@ -0,0 +1,60 @@
+package audio
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Segment is a span of transcribed speech. Transcribers return Start and End relative to the
+// start of the audio file; use Offset to make them absolute within the source video.
+type Segment struct {
+	Start time.Duration
+	End   time.Duration
+	Text  string
+}
+
+// Transcript is the result of transcribing one audio file.
+type Transcript struct {
+	Text     string // full transcript as produced by the backend
+	Segments []Segment
+	Language string // detected or requested language, if known
+}
+
+// Offset returns a copy of segments shifted by d.
+func Offset(segments []Segment, d time.Duration) []Segment {
+	shifted := make([]Segment, len(segments))
+	for i, s := range segments {
+		shifted[i] = Segment{Start: s.Start + d, End: s.End + d, Text: s.Text}
+	}
+	return shifted
+}
+
+// FormatSegments renders segments one per line as "[hh:mm:ss.mmm --> hh:mm:ss.mmm]  text",
+// the same layout whisper-cli prints.
+func FormatSegments(segments []Segment) string {
+	var b strings.Builder
+	for _, s := range segments {
+		fmt.Fprintf(&b, "[%s --> %s]  %s\n", FormatTimestamp(s.Start), FormatTimestamp(s.End), s.Text)
+	}
+	return b.String()
+}
+
+// FormatTimestamp renders d as hh:mm:ss.mmm.
+func FormatTimestamp(d time.Duration) string {
+	ms := d.Milliseconds()
+	return fmt.Sprintf("%02d:%02d:%02d.%03d", ms/3600000, ms/60000%60, ms/1000%60, ms%1000)
+}
+
+// Options are per-call transcription settings.
+type Options struct {
+	Language   string // overrides the transcriber's default language when set
+	VideoIndex int    // only used for logs
+	ChunkNum   int    // only used for logs
+}
+
+// AudioTranscriber turns an audio file into a Transcript.
+type AudioTranscriber interface {
+	Transcribe(ctx context.Context, audioPath string, opts Options) (*Transcript, error)
+}
@@ -0,0 +1,139 @@
+package audio
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/utkarsh-cpu/videoSummaryGo/failure"
+	"github.com/utkarsh-cpu/videoSummaryGo/progress"
+)
+
+// WhisperCLITranscriber transcribes audio by running the whisper.cpp whisper-cli binary.
+type WhisperCLITranscriber struct {
+	CLIPath   string
+	ModelPath string
+	Threads   int
+	Language  string
+}
+
+// Transcribe runs whisper-cli on audioPath with JSON output enabled and returns its segments.
+// If no JSON file is produced the timestamped stdout is parsed instead.
+func (w *WhisperCLITranscriber) Transcribe(ctx context.Context, audioPath string, opts Options) (*Transcript, error) {
+	jsonDir, err := os.MkdirTemp("", "whisper_json")
+	if err != nil {
+		return nil, fmt.Errorf("error creating temporary directory for whisper output: %w", err)
+	}
+	defer os.RemoveAll(jsonDir)
+	outputBase := filepath.Join(jsonDir, "transcript")
+
+	language := w.Language
+	if opts.Language != "" {
+		language = opts.Language
+	}
+	cmdArgs := []string{
+		"--model", w.ModelPath,
+		"--threads", fmt.Sprintf("%d", w.Threads),
+		"--output-json",
+		"--output-file", outputBase,
+	}
+	if language != "" {
+		cmdArgs = append(cmdArgs, "--language", language)
+	}
+	cmdArgs = append(cmdArgs, audioPath)
+
+	cmd := exec.CommandContext(ctx, w.CLIPath, cmdArgs...)
+	var out bytes.Buffer
+	var stderr bytes.Buffer
+	cmd.Stdout = &out
+	cmd.Stderr = &stderr
+
+	progress.Printf(ctx, "Starting whisper-cli for video %d chunk %d, Audio Path: %s", opts.VideoIndex, opts.ChunkNum, audioPath)
+	startTime := time.Now()
+
+	err = cmd.Run()
+	duration := time.Since(startTime)
+	progress.Printf(ctx, "Whisper-cli finished for video %d chunk %d in %v", opts.VideoIndex, opts.ChunkNum, duration)
+
+	if err != nil {
+		return nil, failure.Command(failure.Whisper, fmt.Errorf("error running whisper-cli for video %d chunk %d: %w, stderr: %s", opts.VideoIndex, opts.ChunkNum, err, stderr.String()))
+	}
+
+	text := out.String()
+	data, err := os.ReadFile(outputBase + ".json")
+	if err != nil {
+		return &Transcript{Text: text, Segments: parseWhisperText(text)}, nil
+	}
+	transcript, err := parseWhisperJSON(data)
+	if err != nil {
+		return nil, failure.Wrap(failure.Whisper, fmt.Errorf("error parsing whisper-cli JSON for video %d chunk %d: %w", opts.VideoIndex, opts.ChunkNum, err))
+	}
+	transcript.Text = text
+	return transcript, nil
+}
+
+// whisperJSON is the subset of whisper-cli's --output-json document that we use.
+type whisperJSON struct {
+	Result struct {
+		Language string `json:"language"`
+	} `json:"result"`
+	Transcription []struct {
+		Offsets struct {
+			From int64 `json:"from"` // milliseconds
+			To   int64 `json:"to"`
+		} `json:"offsets"`
+		Text string `json:"text"`
+	} `json:"transcription"`
+}
+
+// parseWhisperJSON converts a whisper-cli JSON document to a Transcript.
+func parseWhisperJSON(data []byte) (*Transcript, error) {
+	var doc whisperJSON
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	transcript := &Transcript{Language: doc.Result.Language}
+	for _, t := range doc.Transcription {
+		transcript.Segments = append(transcript.Segments, Segment{
+			Start: time.Duration(t.Offsets.From) * time.Millisecond,
+			End:   time.Duration(t.Offsets.To) * time.Millisecond,
+			Text:  strings.TrimSpace(t.Text),
+		})
+	}
+	return transcript, nil
+}
+
+// whisperLine matches a whisper-cli stdout line such as "[00:00:01.000 --> 00:00:04.500]  Hello".
+var whisperLine = regexp.MustCompile(`^\[(\d+):(\d+):(\d+)\.(\d+) --> (\d+):(\d+):(\d+)\.(\d+)\]\s*(.*)$`)
+
+// parseWhisperText extracts timestamped segments from whisper-cli's default stdout format.
+func parseWhisperText(text string) []Segment {
+	var segments []Segment
+	scanner := bufio.NewScanner(strings.NewReader(text))
+	for scanner.Scan() {
+		m := whisperLine.FindStringSubmatch(strings.TrimSpace(scanner.Text()))
+		if m == nil {
+			continue
+		}
+		segments = append(segments, Segment{
+			Start: parseClock(m[1], m[2], m[3], m[4]),
+			End:   parseClock(m[5], m[6], m[7], m[8]),
+			Text:  strings.TrimSpace(m[9]),
+		})
+	}
+	return segments
+}
+
+// parseClock converts hh, mm, ss and milliseconds strings to a duration.
+func parseClock(h, m, s, ms string) time.Duration {
+	d, _ := time.ParseDuration(fmt.Sprintf("%sh%sm%ss%sms", h, m, s, ms))
+	return d
+}
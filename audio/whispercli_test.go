@@ -0,0 +1,140 @@
+package audio
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/utkarsh-cpu/videoSummaryGo/failure"
+)
+
+// fixtureSegments are the segments of testdata/whisper.json and testdata/whisper.txt.
+var fixtureSegments = []Segment{
+	{Start: 0, End: 4640 * time.Millisecond, Text: "Welcome back to the channel."},
+	{Start: 4640 * time.Millisecond, End: 9120 * time.Millisecond, Text: "Today we look at the quarterly numbers."},
+	{Start: 9120 * time.Millisecond, End: 62005 * time.Millisecond, Text: "Revenue grew by twelve percent."},
+}
+
+func readFixture(t *testing.T, name string) []byte {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func TestParseWhisperJSON(t *testing.T) {
+	transcript, err := parseWhisperJSON(readFixture(t, "whisper.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if transcript.Language != "en" {
+		t.Errorf("Language = %q, want %q", transcript.Language, "en")
+	}
+	if !slices.Equal(transcript.Segments, fixtureSegments) {
+		t.Errorf("Segments = %+v, want %+v", transcript.Segments, fixtureSegments)
+	}
+
+	if _, err := parseWhisperJSON([]byte(`{"transcription": [`)); err == nil {
+		t.Error("parseWhisperJSON of a truncated document succeeded")
+	}
+}
+
+func TestParseWhisperText(t *testing.T) {
+	if got := parseWhisperText(string(readFixture(t, "whisper.txt"))); !slices.Equal(got, fixtureSegments) {
+		t.Errorf("parseWhisperText = %+v, want %+v", got, fixtureSegments)
+	}
+	if got := parseWhisperText("whisper_init_from_file: loading model\n[not a timestamp]  text\n"); len(got) != 0 {
+		t.Errorf("parseWhisperText of log lines = %+v, want none", got)
+	}
+	// FormatSegments writes the layout parseWhisperText reads.
+	if got := parseWhisperText(FormatSegments(fixtureSegments)); !slices.Equal(got, fixtureSegments) {
+		t.Errorf("round trip = %+v, want %+v", got, fixtureSegments)
+	}
+}
+
+func TestOffset(t *testing.T) {
+	// The third chunk of a video cut every 5 minutes starts at 10:00.
+	shifted := Offset(fixtureSegments, 10*time.Minute)
+	if got, want := FormatTimestamp(shifted[2].Start), "00:10:09.120"; got != want {
+		t.Errorf("shifted start = %s, want %s", got, want)
+	}
+	if got, want := FormatTimestamp(shifted[2].End), "00:11:02.005"; got != want {
+		t.Errorf("shifted end = %s, want %s", got, want)
+	}
+	if shifted[0].Text != fixtureSegments[0].Text || fixtureSegments[0].Start != 0 {
+		t.Error("Offset changed the text or the original segments")
+	}
+}
+
+// fakeWhisper writes a whisper-cli stand-in that prints testdata/whisper.txt and, when writeJSON
+// is set, copies testdata/whisper.json to the --output-file path, like --output-json does.
+func fakeWhisper(t *testing.T, writeJSON bool, exit int) string {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("the whisper-cli stand-in is a shell script")
+	}
+	testdata, err := filepath.Abs("testdata")
+	if err != nil {
+		t.Fatal(err)
+	}
+	script := "#!/bin/sh\n" +
+		"while [ $# -gt 0 ]; do\n" +
+		"  case \"$1\" in --output-file) out=\"$2\"; shift ;; esac\n" +
+		"  shift\n" +
+		"done\n"
+	if writeJSON {
+		script += "cp '" + filepath.Join(testdata, "whisper.json") + "' \"$out.json\"\n"
+	}
+	script += "cat '" + filepath.Join(testdata, "whisper.txt") + "'\n" +
+		"exit " + strconv.Itoa(exit) + "\n"
+	path := filepath.Join(t.TempDir(), "whisper-cli")
+	if err := os.WriteFile(path, []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestWhisperCLITranscribe(t *testing.T) {
+	stdout := string(readFixture(t, "whisper.txt"))
+	for _, tt := range []struct {
+		name     string
+		json     bool
+		language string
+	}{
+		{"output-json", true, "en"},
+		{"stdout fallback", false, ""},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			w := &WhisperCLITranscriber{CLIPath: fakeWhisper(t, tt.json, 0), ModelPath: "model.bin", Threads: 1}
+			transcript, err := w.Transcribe(context.Background(), "chunk.wav", Options{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if transcript.Text != stdout {
+				t.Errorf("Text = %q, want whisper-cli's stdout", transcript.Text)
+			}
+			if transcript.Language != tt.language {
+				t.Errorf("Language = %q, want %q", transcript.Language, tt.language)
+			}
+			if !slices.Equal(transcript.Segments, fixtureSegments) {
+				t.Errorf("Segments = %+v, want %+v", transcript.Segments, fixtureSegments)
+			}
+		})
+	}
+}
+
+func TestWhisperCLIFailure(t *testing.T) {
+	w := &WhisperCLITranscriber{CLIPath: fakeWhisper(t, false, 1), ModelPath: "model.bin", Threads: 1}
+	_, err := w.Transcribe(context.Background(), "chunk.wav", Options{})
+	if err == nil || failure.KindOf(err) != failure.Whisper || !strings.Contains(err.Error(), "whisper-cli") {
+		t.Errorf("Transcribe = %v, want a whisper failure", err)
+	}
+}
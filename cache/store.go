@@ -0,0 +1,172 @@
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// DefaultMaxBytes is the size limit used when Store.MaxBytes is zero.
+const DefaultMaxBytes = 2 << 30 // 2 GiB
+
+// ErrMiss is returned by the caching wrappers in cache-only mode when a result is not cached.
+var ErrMiss = errors.New("not in cache (cache-only mode)")
+
+// Store is a content-addressed on-disk cache. Entries are files named by their key; when the
+// total size exceeds MaxBytes the least recently used entries are evicted.
+type Store struct {
+	Dir      string
+	MaxBytes int64
+	// CacheOnly makes the wrappers return ErrMiss instead of calling the wrapped backend.
+	CacheOnly bool
+
+	mu   sync.Mutex
+	size int64 // -1 until the directory has been scanned
+}
+
+// NewStore returns a Store in dir, creating it if needed. An empty dir uses the user cache directory.
+func NewStore(dir string, maxBytes int64) (*Store, error) {
+	if dir == "" {
+		userCache, err := os.UserCacheDir()
+		if err != nil {
+			return nil, fmt.Errorf("error locating user cache directory: %w", err)
+		}
+		dir = filepath.Join(userCache, "videoSummaryGo")
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("error creating cache directory %s: %w", dir, err)
+	}
+	if maxBytes <= 0 {
+		maxBytes = DefaultMaxBytes
+	}
+	return &Store{Dir: dir, MaxBytes: maxBytes, size: -1}, nil
+}
+
+// Key hashes parts into a cache key. Each part is length-prefixed so ("ab","c") and ("a","bc") differ.
+func Key(parts ...string) string {
+	h := sha256.New()
+	for _, p := range parts {
+		fmt.Fprintf(h, "%d:", len(p))
+		io.WriteString(h, p)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// FileHash returns the SHA-256 of the contents of path.
+func FileHash(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// path returns the file of key, sharded by its first two characters.
+func (s *Store) path(key string) string {
+	return filepath.Join(s.Dir, key[:2], key)
+}
+
+// Get returns the cached value of key and marks it as recently used.
+func (s *Store) Get(key string) ([]byte, bool) {
+	p := s.path(key)
+	data, err := os.ReadFile(p)
+	if err != nil {
+		return nil, false
+	}
+	now := time.Now()
+	os.Chtimes(p, now, now)
+	return data, true
+}
+
+// Put stores value under key and evicts old entries if the cache is over its size limit.
+func (s *Store) Put(key string, value []byte) error {
+	p := s.path(key)
+	if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+		return fmt.Errorf("error creating cache directory: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(p), key+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("error writing cache entry: %w", err)
+	}
+	_, err = tmp.Write(value)
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), p)
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("error writing cache entry: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.size < 0 {
+		s.size = 0
+		for _, e := range s.entries() {
+			s.size += e.size
+		}
+	} else {
+		s.size += int64(len(value))
+	}
+	if s.size > s.MaxBytes {
+		s.evict()
+	}
+	return nil
+}
+
+// cacheEntry is a cached file found on disk.
+type cacheEntry struct {
+	path    string
+	size    int64
+	modTime time.Time
+}
+
+// entries lists every cached file.
+func (s *Store) entries() []cacheEntry {
+	var entries []cacheEntry
+	filepath.WalkDir(s.Dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || filepath.Ext(path) == ".tmp" {
+			return nil
+		}
+		if info, err := d.Info(); err == nil {
+			entries = append(entries, cacheEntry{path: path, size: info.Size(), modTime: info.ModTime()})
+		}
+		return nil
+	})
+	return entries
+}
+
+// evict removes least recently used entries until the cache is at 90% of MaxBytes.
+// The caller holds s.mu.
+func (s *Store) evict() {
+	entries := s.entries()
+	sort.Slice(entries, func(i, j int) bool { return entries[i].modTime.Before(entries[j].modTime) })
+	s.size = 0
+	for _, e := range entries {
+		s.size += e.size
+	}
+	target := s.MaxBytes / 10 * 9
+	for _, e := range entries {
+		if s.size <= target {
+			break
+		}
+		if os.Remove(e.path) == nil {
+			s.size -= e.size
+		}
+	}
+}
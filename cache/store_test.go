@@ -0,0 +1,201 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/utkarsh-cpu/videoSummaryGo/audio"
+	"github.com/utkarsh-cpu/videoSummaryGo/llm"
+	"github.com/utkarsh-cpu/videoSummaryGo/visual"
+)
+
+func TestKey(t *testing.T) {
+	if Key("ab", "c") == Key("a", "bc") {
+		t.Error(`Key("ab", "c") == Key("a", "bc")`)
+	}
+	if Key("a", "") == Key("a") {
+		t.Error(`Key("a", "") == Key("a")`)
+	}
+	if Key("audio", "hash", "model") != Key("audio", "hash", "model") {
+		t.Error("Key is not deterministic")
+	}
+	if k := Key("x"); len(k) != 64 || strings.Trim(k, "0123456789abcdef") != "" {
+		t.Errorf("Key(%q) = %q, want 64 hex digits", "x", k)
+	}
+}
+
+func TestStoreGetPut(t *testing.T) {
+	s, err := NewStore(t.TempDir(), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.MaxBytes != DefaultMaxBytes {
+		t.Errorf("MaxBytes = %d, want %d", s.MaxBytes, DefaultMaxBytes)
+	}
+	key := Key("k")
+	if _, ok := s.Get(key); ok {
+		t.Fatal("Get of a new key succeeded")
+	}
+	if err := s.Put(key, []byte("value")); err != nil {
+		t.Fatal(err)
+	}
+	if got, ok := s.Get(key); !ok || string(got) != "value" {
+		t.Errorf("Get = %q, %v, want %q", got, ok, "value")
+	}
+	if _, err := os.Stat(filepath.Join(s.Dir, key[:2], key)); err != nil {
+		t.Errorf("entry not sharded by key prefix: %v", err)
+	}
+}
+
+func TestStoreEvictsLeastRecentlyUsed(t *testing.T) {
+	s, err := NewStore(t.TempDir(), 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	value := []byte(strings.Repeat("x", 30))
+	keys := []string{Key("a"), Key("b"), Key("c")}
+	for i, key := range keys {
+		if err := s.Put(key, value); err != nil {
+			t.Fatal(err)
+		}
+		// Give the entries distinct, increasing use times: a, then b, then c.
+		at := time.Now().Add(time.Duration(i-10) * time.Minute)
+		os.Chtimes(s.path(key), at, at)
+	}
+	// Reading a makes b the least recently used entry.
+	if _, ok := s.Get(keys[0]); !ok {
+		t.Fatal("a missing before eviction")
+	}
+
+	// 120 bytes exceed the limit; eviction goes down to 90 bytes.
+	if err := s.Put(Key("d"), value); err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range []struct {
+		name string
+		key  string
+		want bool
+	}{{"a", keys[0], true}, {"b", keys[1], false}, {"c", keys[2], true}, {"d", Key("d"), true}} {
+		if _, ok := s.Get(tt.key); ok != tt.want {
+			t.Errorf("entry %s cached = %v, want %v", tt.name, ok, tt.want)
+		}
+	}
+}
+
+type countingAudio struct{ calls int }
+
+func (a *countingAudio) Transcribe(ctx context.Context, audioPath string, opts audio.Options) (*audio.Transcript, error) {
+	a.calls++
+	return &audio.Transcript{Text: "hello", Language: "en"}, nil
+}
+
+type countingVisual struct {
+	name  string
+	calls int
+}
+
+func (v *countingVisual) Name() string { return v.name }
+
+func (v *countingVisual) Transcribe(ctx context.Context, videoPath string, opts visual.Options) (*visual.Result, error) {
+	v.calls++
+	return &visual.Result{Text: "slide", Backend: v.name}, nil
+}
+
+type countingLLM struct {
+	llm.LLM
+	calls int
+}
+
+func (m *countingLLM) Name() string  { return "fake" }
+func (m *countingLLM) Model() string { return "model" }
+
+func (m *countingLLM) Generate(ctx context.Context, parts []llm.Part) (*llm.Response, error) {
+	m.calls++
+	return &llm.Response{Text: "summary"}, nil
+}
+
+// writeFile writes a media file with the given contents and returns its path.
+func writeFile(t *testing.T, name, contents string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(contents), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestWrappers(t *testing.T) {
+	s, err := NewStore(t.TempDir(), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	wav := writeFile(t, "chunk.wav", "audio bytes")
+	mp4 := writeFile(t, "chunk.mp4", "video bytes")
+
+	a := &countingAudio{}
+	at := &AudioTranscriber{Inner: a, Store: s, Identity: "whisper|model"}
+	v := &countingVisual{name: "gemini-video"}
+	vt := &VisualTranscriber{Inner: v, Store: s, Identity: "gemini|flash"}
+	m := &countingLLM{}
+	lt := &LLM{LLM: m, Store: s}
+	for range 2 {
+		if tr, err := at.Transcribe(ctx, wav, audio.Options{}); err != nil || tr.Text != "hello" || tr.Language != "en" {
+			t.Errorf("audio transcript = %+v, %v", tr, err)
+		}
+		if r, err := vt.Transcribe(ctx, mp4, visual.Options{}); err != nil || r.Text != "slide" || r.Backend != "gemini-video" {
+			t.Errorf("visual result = %+v, %v", r, err)
+		}
+		if r, err := lt.Generate(ctx, []llm.Part{llm.Text("summarize")}); err != nil || r.Text != "summary" {
+			t.Errorf("LLM response = %+v, %v", r, err)
+		}
+	}
+	if a.calls != 1 || v.calls != 1 || m.calls != 1 {
+		t.Errorf("backends called %d, %d and %d times, want once each", a.calls, v.calls, m.calls)
+	}
+
+	// A different language, identity or prompt is a different entry.
+	at.Transcribe(ctx, wav, audio.Options{Language: "de"})
+	(&VisualTranscriber{Inner: v, Store: s, Identity: "gemini|pro"}).Transcribe(ctx, mp4, visual.Options{})
+	lt.Generate(ctx, []llm.Part{llm.Text("summarize again")})
+	if a.calls != 2 || v.calls != 2 || m.calls != 2 {
+		t.Errorf("backends called %d, %d and %d times, want twice each", a.calls, v.calls, m.calls)
+	}
+}
+
+func TestCacheOnly(t *testing.T) {
+	s, err := NewStore(t.TempDir(), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.CacheOnly = true
+	ctx := context.Background()
+	wav := writeFile(t, "chunk.wav", "audio bytes")
+	mp4 := writeFile(t, "chunk.mp4", "video bytes")
+
+	a := &countingAudio{}
+	if _, err := (&AudioTranscriber{Inner: a, Store: s}).Transcribe(ctx, wav, audio.Options{}); !errors.Is(err, ErrMiss) {
+		t.Errorf("audio: error = %v, want ErrMiss", err)
+	}
+	v := &countingVisual{name: "tesseract-frames"}
+	if _, err := (&VisualTranscriber{Inner: v, Store: s}).Transcribe(ctx, mp4, visual.Options{}); !errors.Is(err, ErrMiss) {
+		t.Errorf("visual: error = %v, want ErrMiss", err)
+	}
+	m := &countingLLM{}
+	if _, err := (&LLM{LLM: m, Store: s}).Generate(ctx, []llm.Part{llm.Text("hi")}); !errors.Is(err, ErrMiss) {
+		t.Errorf("LLM: error = %v, want ErrMiss", err)
+	}
+	if a.calls+v.calls+m.calls != 0 {
+		t.Error("cache-only mode called a backend")
+	}
+
+	// Prompts with uploaded media are never cached and always reach the backend.
+	if _, err := (&LLM{LLM: m, Store: s}).Generate(ctx, []llm.Part{llm.MediaPart(&llm.Media{URI: "files/1"})}); err != nil || m.calls != 1 {
+		t.Errorf("media prompt: error %v after %d calls", err, m.calls)
+	}
+}
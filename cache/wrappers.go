@@ -0,0 +1,142 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/utkarsh-cpu/videoSummaryGo/audio"
+	"github.com/utkarsh-cpu/videoSummaryGo/llm"
+	"github.com/utkarsh-cpu/videoSummaryGo/progress"
+	"github.com/utkarsh-cpu/videoSummaryGo/visual"
+)
+
+// getJSON decodes the cached value of key into v.
+func (s *Store) getJSON(key string, v any) bool {
+	data, ok := s.Get(key)
+	if !ok {
+		return false
+	}
+	return json.Unmarshal(data, v) == nil
+}
+
+// putJSON caches v under key, reporting failures since the cache is best effort.
+func (s *Store) putJSON(ctx context.Context, key string, v any) {
+	data, err := json.Marshal(v)
+	if err == nil {
+		err = s.Put(key, data)
+	}
+	if err != nil {
+		progress.Printf(ctx, "Error writing cache: %v", err)
+	}
+}
+
+// AudioTranscriber caches transcripts by audio bytes, backend identity and options.
+type AudioTranscriber struct {
+	Inner audio.AudioTranscriber
+	Store *Store
+	// Identity names the backend and every setting that affects its output, e.g. model path.
+	Identity string
+}
+
+// Transcribe returns the cached transcript of audioPath or transcribes and caches it.
+func (t *AudioTranscriber) Transcribe(ctx context.Context, audioPath string, opts audio.Options) (*audio.Transcript, error) {
+	hash, err := FileHash(audioPath)
+	if err != nil {
+		return t.Inner.Transcribe(ctx, audioPath, opts)
+	}
+	key := Key("audio", hash, t.Identity, opts.Language)
+	var cached audio.Transcript
+	if t.Store.getJSON(key, &cached) {
+		progress.Printf(ctx, "Chunk %d for video %d: audio transcript served from cache.", opts.ChunkNum, opts.VideoIndex)
+		return &cached, nil
+	}
+	if t.Store.CacheOnly {
+		return nil, ErrMiss
+	}
+	transcript, err := t.Inner.Transcribe(ctx, audioPath, opts)
+	if err != nil {
+		return nil, err
+	}
+	t.Store.putJSON(ctx, key, transcript)
+	return transcript, nil
+}
+
+// VisualTranscriber caches visual transcriptions by video bytes, backend identity and name.
+type VisualTranscriber struct {
+	Inner    visual.VisualTranscriber
+	Store    *Store
+	Identity string // settings that affect the output beyond Inner.Name(), e.g. the LLM model
+}
+
+// Name returns the wrapped transcriber's name.
+func (t *VisualTranscriber) Name() string {
+	return t.Inner.Name()
+}
+
+// Transcribe returns the cached result for videoPath or transcribes and caches it.
+func (t *VisualTranscriber) Transcribe(ctx context.Context, videoPath string, opts visual.Options) (*visual.Result, error) {
+	hash, err := FileHash(videoPath)
+	if err != nil {
+		return t.Inner.Transcribe(ctx, videoPath, opts)
+	}
+	key := Key("visual", hash, t.Inner.Name(), t.Identity)
+	var cached visual.Result
+	if t.Store.getJSON(key, &cached) {
+		progress.Printf(ctx, "Chunk %d for video %d: visual transcript served from cache.", opts.ChunkNum, opts.VideoIndex)
+		return &cached, nil
+	}
+	if t.Store.CacheOnly {
+		return nil, ErrMiss
+	}
+	result, err := t.Inner.Transcribe(ctx, videoPath, opts)
+	if err != nil {
+		return nil, err
+	}
+	t.Store.putJSON(ctx, key, result)
+	return result, nil
+}
+
+// LLM caches Generate responses by prompt, backend and model. Prompts that reference uploaded
+// media are not cached because upload URIs differ between runs; everything else is passed through.
+type LLM struct {
+	llm.LLM
+	Store *Store
+	// Config identifies generation settings beyond the model name (temperature etc.), if any.
+	Config string
+}
+
+// Generate returns the cached response for parts or generates and caches it.
+func (c *LLM) Generate(ctx context.Context, parts []llm.Part) (*llm.Response, error) {
+	key, ok := c.promptKey(parts)
+	if !ok {
+		return c.LLM.Generate(ctx, parts)
+	}
+	var cached llm.Response
+	if c.Store.getJSON(key, &cached) {
+		progress.Printf(ctx, "LLM response served from cache.")
+		return &cached, nil
+	}
+	if c.Store.CacheOnly {
+		return nil, ErrMiss
+	}
+	resp, err := c.LLM.Generate(ctx, parts)
+	if err != nil {
+		return nil, err
+	}
+	if resp.Text != "" {
+		c.Store.putJSON(ctx, key, resp)
+	}
+	return resp, nil
+}
+
+// promptKey hashes parts with the backend identity. ok is false for uncacheable prompts.
+func (c *LLM) promptKey(parts []llm.Part) (string, bool) {
+	keyParts := []string{"llm", c.LLM.Name(), c.LLM.Model(), c.Config}
+	for _, p := range parts {
+		if p.Media != nil {
+			return "", false
+		}
+		keyParts = append(keyParts, p.Text, p.MIMEType, string(p.Data))
+	}
+	return Key(keyParts...), true
+}
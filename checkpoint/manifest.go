@@ -0,0 +1,146 @@
+package checkpoint
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/utkarsh-cpu/videoSummaryGo/audio"
+)
+
+// ManifestVersion is bumped whenever the manifest format changes; older manifests are ignored.
+const ManifestVersion = 1
+
+// Fingerprint identifies the inputs a manifest was produced from. A manifest is only reused
+// when its fingerprint equals the current one.
+type Fingerprint struct {
+	Source        string        `json:"source"` // absolute path
+	Size          int64         `json:"size"`
+	ModTime       time.Time     `json:"mod_time"`
+	ChunkDuration int           `json:"chunk_duration"`
+	SilenceWindow time.Duration `json:"silence_window,omitempty"`
+	LLMBackend    string        `json:"llm_backend"`
+	LLM           string        `json:"llm"`
+	WhisperModel  string        `json:"whisper_model"`
+	Language      string        `json:"language,omitempty"`     // whisper language
+	OCRLanguage   string        `json:"ocr_language,omitempty"` // tesseract language packs
+	Visual        string        `json:"visual"`                 // visual transcriber name
+	PromptHash    string        `json:"prompt_hash"`            // hash of the prompt templates
+	SkipAudio     bool          `json:"skip_audio,omitempty"`
+	SkipVisual    bool          `json:"skip_visual,omitempty"`
+	SkipSummary   bool          `json:"skip_summary,omitempty"`
+}
+
+// ChunkStatus is the processing state of one chunk.
+type ChunkStatus string
+
+// Chunk states. Failed chunks are processed again on resume.
+const (
+	ChunkDone   ChunkStatus = "done"
+	ChunkFailed ChunkStatus = "failed"
+)
+
+// Chunk is the recorded result of one chunk.
+type Chunk struct {
+	Index           int             `json:"index"`
+	Status          ChunkStatus     `json:"status"`
+	Start           time.Duration   `json:"start"`
+	End             time.Duration   `json:"end"`
+	AudioTranscript string          `json:"audio_transcript"`
+	AudioSegments   []audio.Segment `json:"audio_segments,omitempty"`
+	AudioLanguage   string          `json:"audio_language,omitempty"`
+	VideoTranscript string          `json:"video_transcript"`
+	VideoBackend    string          `json:"video_backend,omitempty"`
+	Errors          []string        `json:"errors,omitempty"`
+}
+
+// Manifest is the checkpoint of one video.
+type Manifest struct {
+	Version     int            `json:"version"`
+	Fingerprint Fingerprint    `json:"fingerprint"`
+	Chunks      map[int]*Chunk `json:"chunks"`
+	SummaryDone bool           `json:"summary_done"`
+	Summary     string         `json:"summary,omitempty"`
+	Complete    bool           `json:"complete"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+}
+
+// New returns an empty manifest for fp.
+func New(fp Fingerprint) *Manifest {
+	return &Manifest{Version: ManifestVersion, Fingerprint: fp, Chunks: map[int]*Chunk{}}
+}
+
+// Load reads the manifest at path. A missing file returns an error wrapping os.ErrNotExist.
+func Load(path string) (*Manifest, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var m Manifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("error parsing manifest %s: %w", path, err)
+	}
+	if m.Chunks == nil {
+		m.Chunks = map[int]*Chunk{}
+	}
+	return &m, nil
+}
+
+// Valid reports whether m was written by this version for the inputs described by fp.
+func (m *Manifest) Valid(fp Fingerprint) bool {
+	return m.Version == ManifestVersion && m.Fingerprint.Source == fp.Source && m.Fingerprint.Size == fp.Size &&
+		m.Fingerprint.ModTime.Equal(fp.ModTime) && m.Fingerprint.ChunkDuration == fp.ChunkDuration &&
+		m.Fingerprint.SilenceWindow == fp.SilenceWindow &&
+		m.Fingerprint.LLMBackend == fp.LLMBackend && m.Fingerprint.LLM == fp.LLM &&
+		m.Fingerprint.WhisperModel == fp.WhisperModel && m.Fingerprint.Language == fp.Language &&
+		m.Fingerprint.OCRLanguage == fp.OCRLanguage && m.Fingerprint.Visual == fp.Visual &&
+		m.Fingerprint.PromptHash == fp.PromptHash && m.Fingerprint.SkipAudio == fp.SkipAudio &&
+		m.Fingerprint.SkipVisual == fp.SkipVisual && m.Fingerprint.SkipSummary == fp.SkipSummary
+}
+
+// Done returns the recorded chunk with index if it completed successfully.
+func (m *Manifest) Done(index int) (*Chunk, bool) {
+	c, ok := m.Chunks[index]
+	return c, ok && c.Status == ChunkDone
+}
+
+// Completed returns the chunks that completed successfully, by index. The returned map is a copy,
+// so it can be read while m is being updated by another goroutine.
+func (m *Manifest) Completed() map[int]*Chunk {
+	done := map[int]*Chunk{}
+	for index := range m.Chunks {
+		if c, ok := m.Done(index); ok {
+			done[index] = c
+		}
+	}
+	return done
+}
+
+// Save writes m to path atomically, so an interrupted write never leaves a truncated manifest.
+func (m *Manifest) Save(path string) error {
+	m.UpdatedAt = time.Now()
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error encoding manifest: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("error creating manifest %s: %w", path, err)
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return fmt.Errorf("error writing manifest %s: %w", path, err)
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("error writing manifest %s: %w", path, err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("error writing manifest %s: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,424 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"text/template"
+
+	"github.com/utkarsh-cpu/videoSummaryGo/cache"
+	"github.com/utkarsh-cpu/videoSummaryGo/config"
+	"github.com/utkarsh-cpu/videoSummaryGo/failure"
+	"github.com/utkarsh-cpu/videoSummaryGo/llm"
+	"github.com/utkarsh-cpu/videoSummaryGo/media"
+	"github.com/utkarsh-cpu/videoSummaryGo/output"
+	"github.com/utkarsh-cpu/videoSummaryGo/pipeline"
+	"github.com/utkarsh-cpu/videoSummaryGo/progress"
+	"github.com/utkarsh-cpu/videoSummaryGo/report"
+	"github.com/utkarsh-cpu/videoSummaryGo/subtitle"
+	"github.com/utkarsh-cpu/videoSummaryGo/summarize"
+	"gopkg.in/yaml.v3"
+)
+
+// newFlagSet returns the flag set of a subcommand whose --help prints usage and description.
+func newFlagSet(name string, args string, description string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s %s [flags] %s\n\n%s\n\nFlags:\n", progName, name, args, description)
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+// runFlags are the flags of the run command.
+type runFlags struct {
+	config  *configFlags
+	llm     *llmFlags
+	whisper *whisperFlags
+	chunk   *chunkFlags
+	ocr     *ocrFlags
+	prompt  *promptFlags
+	output  *outputFlags
+	cache   *cacheFlags
+	formats *formatsFlag
+	// subtitles shape the cues of the SRT and VTT files.
+	subtitles *subtitle.Options
+	embed     string
+	skip      bool
+	// reportTemplate is the path of a custom report template.
+	reportTemplate *string
+}
+
+func addRunFlags(fs *flag.FlagSet) *runFlags {
+	f := &runFlags{
+		config:  addConfigFlags(fs),
+		llm:     addLLMFlags(fs),
+		whisper: addWhisperFlags(fs),
+		chunk:   addChunkFlags(fs),
+		ocr:     addOCRFlags(fs),
+		prompt:  addPromptFlags(fs),
+		output:  addOutputFlags(fs),
+		cache:   addCacheFlags(fs),
+		formats: addFormatsFlag(fs),
+	}
+	f.reportTemplate = addReportTemplateFlag(fs)
+	f.subtitles = addSubtitleFlags(fs)
+	fs.StringVar(&f.embed, "embed", "", "also write a copy of each video with the subtitles: soft-mkv, soft-mp4 or burn (needs srt or vtt; default $EMBED_SUBTITLES)")
+	fs.BoolVar(&f.skip, "skip-checks", false, "do not check tools, models, credentials and disk space before starting")
+	return f
+}
+
+// pipeline returns the pipeline configuration, output layout and run options selected by the flags.
+func (f *runFlags) pipeline(input string) (pipeline.Config, *output.Layout, runOptions, error) {
+	var cfg pipeline.Config
+	opts := runOptions{embedMode: output.EmbedMode(f.embed), skipChecks: f.skip, subtitles: *f.subtitles}
+	switch opts.embedMode {
+	case output.EmbedNone, output.EmbedSoftMKV, output.EmbedSoftMP4, output.EmbedBurn:
+	default:
+		return cfg, nil, opts, fmt.Errorf("unknown subtitle embed mode %q: use soft-mkv, soft-mp4 or burn", opts.embedMode)
+	}
+	var err error
+	if opts.formats, err = f.formats.set(); err != nil {
+		return cfg, nil, opts, err
+	}
+	if opts.reportTemplate, err = loadReportTemplate(*f.reportTemplate); err != nil {
+		return cfg, nil, opts, err
+	}
+
+	if err := f.llm.apply(&cfg); err != nil {
+		return cfg, nil, opts, err
+	}
+	f.whisper.apply(&cfg)
+	if err := f.chunk.apply(&cfg); err != nil {
+		return cfg, nil, opts, err
+	}
+	if err := f.ocr.apply(&cfg); err != nil {
+		return cfg, nil, opts, err
+	}
+	f.prompt.apply(&cfg)
+	layout := f.output.layout(&cfg, input)
+	if cfg.Cache, err = f.cache.store(); err != nil {
+		return cfg, nil, opts, err
+	}
+	return cfg, layout, opts, nil
+}
+
+// runCmd runs the full pipeline.
+func runCmd(ctx context.Context, args []string) error {
+	fs := newFlagSet("run", "<video_or_folder>", "Transcribe the audio and on-screen text of every chunk, summarize each video and write\ntranscripts, summary, JSON result, report and subtitles.")
+	f := addRunFlags(fs)
+	if err := f.config.parse(fs, args); err != nil {
+		return err
+	}
+
+	var input string
+	switch fs.NArg() {
+	case 1:
+		input = fs.Arg(0)
+	case 8:
+		// The original positional form: <llm_model> <api_key> <chunk_duration_seconds> <whisper_cli_path>
+		// <whisper_model_path> <whisper_threads> <whisper_language> <video_path_or_folder>.
+		log.Printf("Positional arguments are deprecated and put the API key in ps output and shell history; use named flags and $GEMINI_API_KEY or --api-key-file instead (see '%s run --help').\n", progName)
+		var err error
+		f.llm.model, f.llm.apiKey = fs.Arg(0), fs.Arg(1)
+		if f.chunk.duration, err = strconv.Atoi(fs.Arg(2)); err != nil {
+			return fmt.Errorf("invalid chunk duration: %w", err)
+		}
+		f.whisper.cliPath, f.whisper.modelPath = fs.Arg(3), fs.Arg(4)
+		if f.whisper.threads, err = strconv.Atoi(fs.Arg(5)); err != nil {
+			return fmt.Errorf("invalid whisper threads: %w", err)
+		}
+		f.whisper.language = fs.Arg(6)
+		input = fs.Arg(7)
+	default:
+		fs.Usage()
+		return errUsage
+	}
+
+	cfg, layout, opts, err := f.pipeline(input)
+	if err != nil {
+		return err
+	}
+	return VideoSummary(ctx, cfg, input, layout, opts)
+}
+
+// transcribeCmd transcribes audio only.
+func transcribeCmd(ctx context.Context, args []string) error {
+	fs := newFlagSet("transcribe", "<video_or_folder>", "Transcribe only the audio of each video with whisper-cli and write the timestamped\ntranscript, SRT/VTT subtitles and JSON result. No LLM is used.")
+	configF := addConfigFlags(fs)
+	whisperF := addWhisperFlags(fs)
+	chunkF := addChunkFlags(fs)
+	outF := addOutputFlags(fs)
+	cacheF := addCacheFlags(fs)
+	subtitleOpts := addSubtitleFlags(fs)
+	if err := configF.parse(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return errUsage
+	}
+	input := fs.Arg(0)
+
+	cfg := pipeline.Config{SkipVisual: true, SkipSummary: true}
+	whisperF.apply(&cfg)
+	if err := chunkF.apply(&cfg); err != nil {
+		return err
+	}
+	layout := outF.layout(&cfg, input)
+	store, err := cacheF.store()
+	if err != nil {
+		return err
+	}
+	cfg.Cache = store
+	return runPipeline(ctx, cfg, input, layout, nil, func(ctx context.Context, result *pipeline.VideoResult, dir string) {
+		if err := output.WriteAudioTranscript(result, dir); err != nil {
+			log.Println(err)
+		}
+		if _, err := output.WriteJSON(result, dir); err != nil {
+			log.Println(err)
+		}
+		if _, err := output.WriteSubtitles(result, dir, []subtitle.Format{subtitle.SRT, subtitle.VTT}, *subtitleOpts); err != nil {
+			log.Println(err)
+		}
+	})
+}
+
+// ocrCmd transcribes on-screen text only.
+func ocrCmd(ctx context.Context, args []string) error {
+	fs := newFlagSet("ocr", "<video_or_folder>", "Transcribe only the on-screen text of each video and write the visual transcript and JSON\nresult. With --visual tesseract no LLM is used.")
+	configF := addConfigFlags(fs)
+	llmF := addLLMFlags(fs)
+	chunkF := addChunkFlags(fs)
+	ocrF := addOCRFlags(fs)
+	promptF := addPromptFlags(fs)
+	outF := addOutputFlags(fs)
+	cacheF := addCacheFlags(fs)
+	if err := configF.parse(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return errUsage
+	}
+	input := fs.Arg(0)
+
+	cfg := pipeline.Config{SkipAudio: true, SkipSummary: true}
+	if err := llmF.apply(&cfg); err != nil {
+		return err
+	}
+	if err := chunkF.apply(&cfg); err != nil {
+		return err
+	}
+	if err := ocrF.apply(&cfg); err != nil {
+		return err
+	}
+	promptF.apply(&cfg)
+	layout := outF.layout(&cfg, input)
+	store, err := cacheF.store()
+	if err != nil {
+		return err
+	}
+	cfg.Cache = store
+	return runPipeline(ctx, cfg, input, layout, nil, func(ctx context.Context, result *pipeline.VideoResult, dir string) {
+		if err := output.WriteVideoTranscript(result, dir); err != nil {
+			log.Println(err)
+		}
+		if _, err := output.WriteJSON(result, dir); err != nil {
+			log.Println(err)
+		}
+	})
+}
+
+// summarizeCmd re-summarizes videos from their JSON results.
+func summarizeCmd(ctx context.Context, args []string) error {
+	fs := newFlagSet("summarize", "<name>_result.json...", "Summarize videos again from the transcripts stored in their JSON results, without re-running\nwhisper or visual transcription, and rewrite their summary, JSON result and report.")
+	configF := addConfigFlags(fs)
+	llmF := addLLMFlags(fs)
+	promptF := addPromptFlags(fs)
+	cacheF := addCacheFlags(fs)
+	templatePath := addReportTemplateFlag(fs)
+	pdf := fs.Bool("pdf", false, "also render the report as PDF (Windows-1252 characters only; the Markdown report is complete)")
+	outDir := fs.String("output-dir", "", "write the outputs to this directory (default: next to each result file)")
+	if err := configF.parse(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return errUsage
+	}
+
+	tmpl, err := loadReportTemplate(*templatePath)
+	if err != nil {
+		return err
+	}
+	ctx = progress.NewContext(ctx, progressLog)
+	opts, err := llmF.options()
+	if err != nil {
+		return err
+	}
+	model, err := llm.New(ctx, opts)
+	if err != nil {
+		return err
+	}
+	defer model.Close()
+	store, err := cacheF.store()
+	if err != nil {
+		return err
+	}
+	if store != nil {
+		model = &cache.LLM{LLM: model, Store: store}
+	}
+
+	failed := 0
+	for _, path := range fs.Args() {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if err := resummarize(ctx, model, promptF.summary, tmpl, *pdf, path, *outDir); err != nil {
+			log.Println(err)
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d results: %w", failed, fs.NArg(), errPartialFailure)
+	}
+	return nil
+}
+
+// resummarize summarizes the result stored at path with the given instructions and rewrites its
+// outputs in outDir, or next to path when outDir is empty. The report is rendered with tmpl, or
+// the built-in template when nil, and also as PDF when pdf is set.
+func resummarize(ctx context.Context, model llm.LLM, instructions string, tmpl *template.Template, pdf bool, path string, outDir string) error {
+	doc, err := output.ReadJSON(path)
+	if err != nil {
+		return err
+	}
+	result := doc.VideoResult()
+	summary, err := summarize.Summarize(ctx, model, instructions, result.AudioTranscript(), result.VideoTranscript(), result.VideoIndex)
+	if err == nil && summary == "" {
+		err = failure.Wrap(failure.LLM, errors.New("LLM returned no summary"))
+	}
+
+	// The new attempt replaces any summary failure recorded by the earlier run.
+	failures := result.Failures[:0]
+	for _, f := range result.Failures {
+		if f.Stage != pipeline.StageSummary {
+			failures = append(failures, f)
+		}
+	}
+	result.Failures = failures
+	if err != nil {
+		err = fmt.Errorf("error summarizing %s: %w", path, err)
+		result.Failures = append(result.Failures, failure.Failure{Stage: pipeline.StageSummary, Chunk: -1, Err: err})
+	} else {
+		result.Summary = summary
+	}
+
+	if outDir == "" {
+		outDir = filepath.Dir(path)
+	}
+	if mkErr := os.MkdirAll(outDir, 0o755); mkErr != nil {
+		return fmt.Errorf("error creating output directory %s: %w", outDir, mkErr)
+	}
+	fmt.Println("Writing outputs to:", outDir)
+	if werr := output.WriteSummary(result, outDir); werr != nil {
+		log.Println(werr)
+	}
+	if _, werr := output.WriteJSON(result, outDir); werr != nil {
+		log.Println(werr)
+	}
+	if _, werr := output.WriteReport(ctx, result, outDir, tmpl, pdf); werr != nil {
+		log.Println(werr)
+	}
+	return err
+}
+
+// probeCmd prints ffprobe information.
+func probeCmd(ctx context.Context, args []string) error {
+	fs := newFlagSet("probe", "<video>...", "Print the container, duration and streams of each video as reported by ffprobe.")
+	asJSON := fs.Bool("json", false, "print ffprobe's complete JSON output")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return errUsage
+	}
+
+	var errs []error
+	for _, path := range fs.Args() {
+		info, err := media.Probe(ctx, path)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if *asJSON {
+			fmt.Println(string(info.Raw))
+			continue
+		}
+		fmt.Println(path)
+		fmt.Printf("  format:   %s\n", info.Format.FormatName)
+		fmt.Printf("  duration: %s\n", report.Clock(info.Duration()))
+		if info.Format.Size != "" {
+			fmt.Printf("  size:     %s bytes\n", info.Format.Size)
+		}
+		if info.Format.BitRate != "" {
+			fmt.Printf("  bitrate:  %s bit/s\n", info.Format.BitRate)
+		}
+		for _, s := range info.Streams {
+			switch {
+			case s.Width > 0:
+				fmt.Printf("  stream %d: %s %s %dx%d\n", s.Index, s.CodecType, s.CodecName, s.Width, s.Height)
+			case s.Channels > 0:
+				fmt.Printf("  stream %d: %s %s %d channels\n", s.Index, s.CodecType, s.CodecName, s.Channels)
+			default:
+				fmt.Printf("  stream %d: %s %s\n", s.Index, s.CodecType, s.CodecName)
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// configCmd prints the effective configuration.
+func configCmd(ctx context.Context, args []string) error {
+	if len(args) == 0 || args[0] != "show" {
+		fmt.Fprintf(os.Stderr, "Usage: %s config show [flags]\n", progName)
+		return errUsage
+	}
+	fs := newFlagSet("config show", "", "Print the settings 'run' would use as YAML, with secrets redacted. Flags override environment\nvariables, which override the selected profile, which overrides the config file defaults,\nwhich override the built-in defaults.")
+	f := addRunFlags(fs)
+	if err := f.config.parse(fs, args[1:]); err != nil {
+		return err
+	}
+
+	var settings config.Settings
+	var err error
+	fs.VisitAll(func(fl *flag.Flag) {
+		if _, serr := settings.Set(fl.Name, fl.Value.String()); serr != nil && err == nil {
+			err = fmt.Errorf("invalid setting for --%s: %w", fl.Name, serr)
+		}
+	})
+	if err != nil {
+		return err
+	}
+	_, path, profile, _ := f.config.settings()
+	if path == "" {
+		path = "none"
+	}
+	fmt.Printf("# config file: %s\n", path)
+	if profile != "" {
+		fmt.Printf("# profile: %s\n", profile)
+	}
+	enc := yaml.NewEncoder(os.Stdout)
+	enc.SetIndent(2)
+	if err := enc.Encode(settings.Redacted()); err != nil {
+		return err
+	}
+	return enc.Close()
+}
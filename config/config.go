@@ -0,0 +1,406 @@
+// Package config reads layered settings from a YAML config file with named profiles and from
+// environment variables. Every setting corresponds to a command-line flag of the same name.
+package config
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/utkarsh-cpu/videoSummaryGo/secret"
+)
+
+// Environment variables that select the config file and profile.
+const (
+	EnvConfig  = "VIDEOSUMMARY_CONFIG"
+	EnvProfile = "VIDEOSUMMARY_PROFILE"
+	// EnvPrefix followed by the upper-cased flag name, with dashes as underscores, sets a
+	// setting, e.g. VIDEOSUMMARY_CHUNK_DURATION.
+	EnvPrefix = "VIDEOSUMMARY_"
+)
+
+// Settings holds every configurable setting. Zero values are unset and leave the layer below in
+// place. The flag tag names the corresponding command-line flag; the env tag, when present,
+// replaces the environment variable derived from it.
+type Settings struct {
+	LLM     LLMSettings     `yaml:"llm,omitempty"`
+	Chunks  ChunkSettings   `yaml:"chunks,omitempty"`
+	Whisper WhisperSettings `yaml:"whisper,omitempty"`
+	OCR     OCRSettings     `yaml:"ocr,omitempty"`
+	Prompts PromptSettings  `yaml:"prompts,omitempty"`
+	Output  OutputSettings  `yaml:"output,omitempty"`
+	Cache   CacheSettings   `yaml:"cache,omitempty"`
+}
+
+// LLMSettings select and configure the LLM backend.
+type LLMSettings struct {
+	Model      string        `yaml:"model,omitempty" flag:"model"`
+	APIKey     string        `yaml:"api_key,omitempty" flag:"api-key" secret:"true"`
+	APIKeyFile string        `yaml:"api_key_file,omitempty" flag:"api-key-file"`
+	BaseURL    string        `yaml:"base_url,omitempty" flag:"base-url"`
+	Attempts   int           `yaml:"attempts,omitempty" flag:"llm-attempts"`
+	MaxDelay   time.Duration `yaml:"max_delay,omitempty" flag:"llm-max-delay"`
+
+	UploadTimeout         time.Duration `yaml:"upload_timeout,omitempty" flag:"upload-timeout"`
+	UploadPollInterval    time.Duration `yaml:"upload_poll_interval,omitempty" flag:"upload-poll-interval"`
+	UploadMaxPollInterval time.Duration `yaml:"upload_max_poll_interval,omitempty" flag:"upload-max-poll-interval"`
+}
+
+// ChunkSettings control chunking and concurrency.
+type ChunkSettings struct {
+	Duration           int           `yaml:"duration,omitempty" flag:"chunk-duration"`
+	SilenceWindow      time.Duration `yaml:"silence_window,omitempty" flag:"silence-window"`
+	Workers            int           `yaml:"workers,omitempty" flag:"workers"`
+	WhisperConcurrency int           `yaml:"whisper_concurrency,omitempty" flag:"whisper-concurrency"`
+	VisualConcurrency  int           `yaml:"visual_concurrency,omitempty" flag:"visual-concurrency"`
+}
+
+// WhisperSettings configure the whisper-cli audio transcriber.
+type WhisperSettings struct {
+	CLI      string `yaml:"cli,omitempty" flag:"whisper-cli"`
+	Model    string `yaml:"model,omitempty" flag:"whisper-model"`
+	Threads  int    `yaml:"threads,omitempty" flag:"whisper-threads"`
+	Language string `yaml:"language,omitempty" flag:"language"`
+}
+
+// OCRSettings configure visual transcription.
+type OCRSettings struct {
+	Backend  string `yaml:"backend,omitempty" flag:"visual"`
+	Language string `yaml:"language,omitempty" flag:"ocr-language"`
+}
+
+// PromptSettings replace the built-in LLM instructions.
+type PromptSettings struct {
+	Summary string `yaml:"summary,omitempty" flag:"summary-prompt"`
+	Visual  string `yaml:"visual,omitempty" flag:"visual-prompt"`
+}
+
+// OutputSettings choose where outputs go and which are written.
+type OutputSettings struct {
+	Dir     string   `yaml:"dir,omitempty" flag:"output-dir"`
+	Formats []string `yaml:"formats,omitempty" flag:"formats"`
+	Embed   string   `yaml:"embed,omitempty" flag:"embed" env:"EMBED_SUBTITLES"`
+
+	ReportTemplate string `yaml:"report_template,omitempty" flag:"report-template"`
+
+	SubtitleMaxLine int           `yaml:"subtitle_max_line,omitempty" flag:"subtitle-max-line"`
+	SubtitleMaxCue  time.Duration `yaml:"subtitle_max_cue,omitempty" flag:"subtitle-max-cue"`
+}
+
+// CacheSettings configure the transcription and LLM response cache.
+type CacheSettings struct {
+	Enabled *bool  `yaml:"enabled,omitempty" flag:"cache"`
+	Dir     string `yaml:"dir,omitempty" flag:"cache-dir"`
+	MaxMB   int64  `yaml:"max_mb,omitempty" flag:"cache-max-mb"`
+}
+
+// File is a config file: defaults for every run plus named profiles layered on top of them.
+type File struct {
+	// Profile is used when neither --profile nor $VIDEOSUMMARY_PROFILE selects one.
+	Profile  string              `yaml:"profile,omitempty"`
+	Defaults Settings            `yaml:"defaults,omitempty"`
+	Profiles map[string]Settings `yaml:"profiles,omitempty"`
+}
+
+// DefaultPath returns <user config dir>/videoSummaryGo/config.yaml.
+func DefaultPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "videoSummaryGo", "config.yaml"), nil
+}
+
+// Find returns the config file to use: path if set, else $VIDEOSUMMARY_CONFIG, else DefaultPath
+// if that file exists. It returns "" when there is none.
+func Find(path string) string {
+	if path != "" {
+		return path
+	}
+	if path = os.Getenv(EnvConfig); path != "" {
+		return path
+	}
+	if path, err := DefaultPath(); err == nil {
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+	}
+	return ""
+}
+
+// Load reads the config file at path. Unknown keys are errors, so typos do not go unnoticed. A
+// file that contains an API key must not be accessible by other users.
+func Load(path string) (*File, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading config file: %w", err)
+	}
+	defer f.Close()
+
+	var file File
+	dec := yaml.NewDecoder(f)
+	dec.KnownFields(true)
+	if err := dec.Decode(&file); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("error parsing config file %s: %w", path, err)
+	}
+	if file.hasSecrets() {
+		if err := CheckPrivate(path); err != nil {
+			return nil, fmt.Errorf("config file contains an API key: %w", err)
+		}
+	}
+	return &file, nil
+}
+
+// hasSecrets reports whether the defaults or any profile set a secret.
+func (f *File) hasSecrets() bool {
+	all := []Settings{f.Defaults}
+	for _, p := range f.Profiles {
+		all = append(all, p)
+	}
+	for _, s := range all {
+		for _, f := range s.fields() {
+			if f.field.Tag.Get("secret") == "true" && !f.value.IsZero() {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Settings returns the file defaults with the named profile merged on top. An empty name selects
+// the file's own profile key, if any.
+func (f *File) Settings(profile string) (Settings, error) {
+	s := f.Defaults
+	if profile == "" {
+		profile = f.Profile
+	}
+	if profile == "" {
+		return s, nil
+	}
+	p, ok := f.Profiles[profile]
+	if !ok {
+		return s, fmt.Errorf("unknown profile %q: the config file defines %s", profile, f.profileNames())
+	}
+	s.Merge(p)
+	return s, nil
+}
+
+// profileNames lists the profiles for error messages.
+func (f *File) profileNames() string {
+	if len(f.Profiles) == 0 {
+		return "no profiles"
+	}
+	names := make([]string, 0, len(f.Profiles))
+	for name := range f.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
+
+// FromEnv returns the settings given by environment variables.
+func FromEnv(getenv func(string) string) (Settings, error) {
+	var s Settings
+	for _, f := range s.fields() {
+		value := getenv(f.env())
+		if value == "" {
+			continue
+		}
+		if err := parse(f.value, value); err != nil {
+			return s, fmt.Errorf("invalid %s: %w", f.env(), err)
+		}
+	}
+	return s, nil
+}
+
+// Merge sets every setting that is set in over.
+func (s *Settings) Merge(over Settings) {
+	dst := s.fields()
+	for i, f := range over.fields() {
+		if !f.value.IsZero() {
+			dst[i].value.Set(f.value)
+		}
+	}
+}
+
+// Values returns the set settings as flag values keyed by flag name.
+func (s *Settings) Values() map[string]string {
+	values := map[string]string{}
+	for _, f := range s.fields() {
+		if !f.value.IsZero() {
+			values[f.flag()] = format(f.value)
+		}
+	}
+	return values
+}
+
+// Set sets the setting of the named flag from its flag value. It reports false if no setting
+// corresponds to the flag.
+func (s *Settings) Set(flag string, value string) (bool, error) {
+	for _, f := range s.fields() {
+		if f.flag() == flag {
+			return true, parse(f.value, value)
+		}
+	}
+	return false, nil
+}
+
+// IsSecret reports whether the setting of the named flag is a secret such as an API key.
+func IsSecret(flag string) bool {
+	var s Settings
+	for _, f := range s.fields() {
+		if f.flag() == flag {
+			return f.field.Tag.Get("secret") == "true"
+		}
+	}
+	return false
+}
+
+// Redacted returns a copy of s with secrets such as API keys masked.
+func (s Settings) Redacted() Settings {
+	for _, f := range s.fields() {
+		if f.field.Tag.Get("secret") == "true" && !f.value.IsZero() {
+			f.value.SetString(secret.Mask)
+		}
+	}
+	return s
+}
+
+// setting is one leaf field of Settings.
+type setting struct {
+	field reflect.StructField
+	value reflect.Value
+}
+
+func (f setting) flag() string {
+	return f.field.Tag.Get("flag")
+}
+
+func (f setting) env() string {
+	if env := f.field.Tag.Get("env"); env != "" {
+		return env
+	}
+	return EnvPrefix + strings.ToUpper(strings.ReplaceAll(f.flag(), "-", "_"))
+}
+
+// fields returns the leaf settings of s in declaration order.
+func (s *Settings) fields() []setting {
+	var out []setting
+	groups := reflect.ValueOf(s).Elem()
+	for i := 0; i < groups.NumField(); i++ {
+		group := groups.Field(i)
+		for j := 0; j < group.NumField(); j++ {
+			out = append(out, setting{group.Type().Field(j), group.Field(j)})
+		}
+	}
+	return out
+}
+
+// format returns v as a flag value.
+func format(v reflect.Value) string {
+	switch x := v.Interface().(type) {
+	case time.Duration:
+		return x.String()
+	case *bool:
+		return strconv.FormatBool(*x)
+	case []string:
+		return strings.Join(x, ",")
+	}
+	return fmt.Sprint(v.Interface())
+}
+
+// parse sets v from a flag value.
+func parse(v reflect.Value, value string) error {
+	switch v.Interface().(type) {
+	case time.Duration:
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return err
+		}
+		v.SetInt(int64(d))
+	case *bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return err
+		}
+		v.Set(reflect.ValueOf(&b))
+	case []string:
+		v.Set(reflect.ValueOf(SplitList(value)))
+	case string:
+		v.SetString(value)
+	case int, int64:
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return err
+		}
+		v.SetInt(n)
+	default:
+		return fmt.Errorf("unsupported setting type %s", v.Type())
+	}
+	return nil
+}
+
+// ReadSecret reads a secret such as an API key from the first line of the file at path, or of
+// standard input when path is "-". The file must not be accessible by other users.
+func ReadSecret(path string) (string, error) {
+	in := os.Stdin
+	name := "standard input"
+	if path != "-" {
+		if err := CheckPrivate(path); err != nil {
+			return "", err
+		}
+		f, err := os.Open(path)
+		if err != nil {
+			return "", fmt.Errorf("error reading credentials file: %w", err)
+		}
+		defer f.Close()
+		in, name = f, path
+	} else if info, err := in.Stat(); err == nil && info.Mode()&os.ModeCharDevice != 0 {
+		fmt.Fprint(os.Stderr, "API key: ")
+	}
+
+	line, err := bufio.NewReader(in).ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return "", fmt.Errorf("error reading %s: %w", name, err)
+	}
+	value := strings.TrimSpace(line)
+	if value == "" {
+		return "", fmt.Errorf("no API key found in %s", name)
+	}
+	return value, nil
+}
+
+// CheckPrivate returns an error if the file at path is accessible by users other than its owner.
+// Windows does not use permission bits, so it is not checked there.
+func CheckPrivate(path string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return fmt.Errorf("error reading credentials file: %w", err)
+	}
+	if perm := info.Mode().Perm(); runtime.GOOS != "windows" && perm&0o077 != 0 {
+		return fmt.Errorf("%s is accessible by other users (mode %04o); restrict it with: chmod 600 %s", path, perm, path)
+	}
+	return nil
+}
+
+// SplitList splits a comma-separated list, dropping empty items and surrounding spaces.
+func SplitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -0,0 +1,184 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/utkarsh-cpu/videoSummaryGo/secret"
+)
+
+const testConfig = `
+profile: fast
+defaults:
+  llm:
+    model: gemini-1.5-flash
+  chunks:
+    duration: 300
+    workers: 2
+  output:
+    formats: [txt, json]
+profiles:
+  fast:
+    chunks:
+      workers: 8
+  local:
+    llm:
+      model: ollama:llama3
+      base_url: http://localhost:11434
+    chunks:
+      silence_window: 20s
+`
+
+// writeConfig writes contents to a private config file and returns its path.
+func writeConfig(t *testing.T, contents string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(contents), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadUnknownKey(t *testing.T) {
+	_, err := Load(writeConfig(t, "defaults:\n  chunks:\n    durration: 300\n"))
+	if err == nil || !strings.Contains(err.Error(), "durration") {
+		t.Errorf("Load = %v, want an error naming the unknown key", err)
+	}
+	if _, err := Load(writeConfig(t, "")); err != nil {
+		t.Errorf("Load of an empty file: %v", err)
+	}
+}
+
+func TestLoadSecretNeedsPrivateFile(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("permission bits are not checked on Windows")
+	}
+	path := writeConfig(t, "defaults:\n  llm:\n    api_key: AIzaSyConfigTestKey\n")
+	if _, err := Load(path); err != nil {
+		t.Fatalf("Load of a private file: %v", err)
+	}
+	if err := os.Chmod(path, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(path); err == nil {
+		t.Error("Load of a world-readable file with an API key succeeded")
+	}
+}
+
+func TestSettingsProfile(t *testing.T) {
+	file, err := Load(writeConfig(t, testConfig))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		profile  string
+		model    string
+		workers  int
+		duration int
+		silence  time.Duration
+		formats  int
+	}{
+		{"", "gemini-1.5-flash", 8, 300, 0, 2}, // the file's profile key
+		{"fast", "gemini-1.5-flash", 8, 300, 0, 2},
+		{"local", "ollama:llama3", 2, 300, 20 * time.Second, 2},
+	}
+	for _, tt := range tests {
+		s, err := file.Settings(tt.profile)
+		if err != nil {
+			t.Errorf("Settings(%q): %v", tt.profile, err)
+			continue
+		}
+		if s.LLM.Model != tt.model || s.Chunks.Workers != tt.workers || s.Chunks.Duration != tt.duration ||
+			s.Chunks.SilenceWindow != tt.silence || len(s.Output.Formats) != tt.formats {
+			t.Errorf("Settings(%q) = %+v", tt.profile, s)
+		}
+	}
+
+	file.Profile = ""
+	if s, err := file.Settings(""); err != nil || s.Chunks.Workers != 2 {
+		t.Errorf("Settings without a profile = %+v, %v; want the defaults", s, err)
+	}
+	if _, err := file.Settings("slow"); err == nil || !strings.Contains(err.Error(), "fast, local") {
+		t.Errorf("Settings(%q) = %v, want an error listing the profiles", "slow", err)
+	}
+}
+
+func TestEnvOverridesFile(t *testing.T) {
+	file, err := Load(writeConfig(t, testConfig))
+	if err != nil {
+		t.Fatal(err)
+	}
+	settings, err := file.Settings("local")
+	if err != nil {
+		t.Fatal(err)
+	}
+	env := map[string]string{
+		"VIDEOSUMMARY_MODEL":          "gemini-1.5-pro",
+		"VIDEOSUMMARY_SILENCE_WINDOW": "5s",
+		"VIDEOSUMMARY_FORMATS":        "md, srt",
+		"EMBED_SUBTITLES":             "soft",
+		"VIDEOSUMMARY_CACHE":          "false",
+	}
+	over, err := FromEnv(func(name string) string { return env[name] })
+	if err != nil {
+		t.Fatal(err)
+	}
+	settings.Merge(over)
+
+	want := map[string]string{
+		"model":          "gemini-1.5-pro",         // environment over profile
+		"base-url":       "http://localhost:11434", // profile, not set in the environment
+		"chunk-duration": "300",                    // file defaults
+		"workers":        "2",
+		"silence-window": "5s",
+		"formats":        "md,srt",
+		"embed":          "soft", // env tag instead of the derived name
+		"cache":          "false",
+	}
+	got := settings.Values()
+	for name, value := range want {
+		if got[name] != value {
+			t.Errorf("--%s = %q, want %q", name, got[name], value)
+		}
+	}
+	if len(got) != len(want) {
+		t.Errorf("Values = %v, want %d settings", got, len(want))
+	}
+
+	if _, err := FromEnv(func(name string) string {
+		if name == "VIDEOSUMMARY_WORKERS" {
+			return "many"
+		}
+		return ""
+	}); err == nil || !strings.Contains(err.Error(), "VIDEOSUMMARY_WORKERS") {
+		t.Errorf("FromEnv with an invalid number = %v, want an error naming the variable", err)
+	}
+}
+
+func TestSetAndRedacted(t *testing.T) {
+	var s Settings
+	if ok, err := s.Set("subtitle-max-cue", "4s"); !ok || err != nil || s.Output.SubtitleMaxCue != 4*time.Second {
+		t.Errorf("Set(subtitle-max-cue) = %v, %v; got %v", ok, err, s.Output.SubtitleMaxCue)
+	}
+	if ok, _ := s.Set("no-such-flag", "1"); ok {
+		t.Error("Set of an unknown flag reported true")
+	}
+	if _, err := s.Set("workers", "x"); err == nil {
+		t.Error("Set(workers, x) succeeded")
+	}
+
+	s.LLM.APIKey = "AIzaSyConfigTestKey"
+	if r := s.Redacted(); r.LLM.APIKey != secret.Mask || r.Output.SubtitleMaxCue != 4*time.Second {
+		t.Errorf("Redacted = %+v", r)
+	}
+	if s.LLM.APIKey != "AIzaSyConfigTestKey" {
+		t.Error("Redacted modified the original settings")
+	}
+	if !IsSecret("api-key") || IsSecret("model") {
+		t.Error("IsSecret does not match the secret tags")
+	}
+}
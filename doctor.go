@@ -0,0 +1,41 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/utkarsh-cpu/videoSummaryGo/preflight"
+)
+
+// doctorCmd checks everything run needs with the same flags.
+func doctorCmd(ctx context.Context, args []string) error {
+	fs := newFlagSet("doctor", "[video_or_folder]", "Check what 'run' with the same flags needs: ffmpeg and ffprobe with the required encoders,\nwhisper-cli and its model, tesseract and its language packs, the LLM credentials and free\nspace in the temp directory (for the largest video below the input, if given). 'run' does\nthe same before starting unless --skip-checks is given.")
+	f := addRunFlags(fs)
+	if err := f.config.parse(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() > 1 {
+		fs.Usage()
+		return errUsage
+	}
+	input := fs.Arg(0)
+	cfg, _, opts, err := f.pipeline(input)
+	if err != nil {
+		return err
+	}
+
+	report := preflight.Run(ctx, cfg, preflight.Options{Input: input, Embed: opts.embedMode})
+	report.Print(os.Stdout)
+	if report.Failed() {
+		failed := 0
+		for _, c := range report {
+			if c.Status == preflight.Fail {
+				failed++
+			}
+		}
+		return fmt.Errorf("%d of %d checks failed; see the fixes above", failed, len(report))
+	}
+	fmt.Println("All required checks passed.")
+	return nil
+}
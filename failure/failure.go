@@ -0,0 +1,135 @@
+package failure
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os/exec"
+	"sync"
+)
+
+// Kind classifies what went wrong. A Kind is also an error so callers can test for it with
+// errors.Is(err, failure.LLMQuota).
+type Kind string
+
+// Failure kinds.
+const (
+	Unknown     Kind = "unknown"
+	ToolMissing Kind = "tool_missing" // a required external program is not installed
+	FFmpeg      Kind = "ffmpeg"       // ffmpeg or ffprobe failed
+	Whisper     Kind = "whisper"      // whisper-cli failed
+	OCR         Kind = "ocr"          // tesseract failed
+	Upload      Kind = "upload"       // uploading media to the LLM provider failed
+	LLM         Kind = "llm"          // any other LLM API failure
+	LLMQuota    Kind = "llm_quota"    // rate limited or out of quota
+	LLMAuth     Kind = "llm_auth"     // missing or invalid credentials
+	LLMBlocked  Kind = "llm_blocked"  // prompt or response blocked by the provider's safety filters
+	Interrupted Kind = "interrupted"  // the run was cancelled
+)
+
+func (k Kind) Error() string {
+	return string(k)
+}
+
+// Error is an error tagged with its Kind.
+type Error struct {
+	Kind Kind
+	Err  error
+}
+
+func (e *Error) Error() string {
+	return e.Err.Error()
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether target is e's Kind.
+func (e *Error) Is(target error) bool {
+	k, ok := target.(Kind)
+	return ok && k == e.Kind
+}
+
+// Wrap tags err with kind. Errors that already carry a kind keep it, so the most specific
+// classification (e.g. LLMQuota inside an Upload failure) wins. Wrap returns nil for a nil err.
+func Wrap(kind Kind, err error) error {
+	if err == nil {
+		return nil
+	}
+	var e *Error
+	if errors.As(err, &e) {
+		return err
+	}
+	return &Error{Kind: kind, Err: err}
+}
+
+// Command tags the error of running an external program: ToolMissing when the program could
+// not be found, kind otherwise.
+func Command(kind Kind, err error) error {
+	if errors.Is(err, exec.ErrNotFound) || errors.Is(err, fs.ErrNotExist) {
+		kind = ToolMissing
+	}
+	return Wrap(kind, err)
+}
+
+// KindOf returns the kind of err: Interrupted for context cancellation, the kind it was wrapped
+// with, or Unknown.
+func KindOf(err error) Kind {
+	var e *Error
+	switch {
+	case errors.As(err, &e):
+		return e.Kind
+	case errors.Is(err, context.Canceled):
+		return Interrupted
+	default:
+		return Unknown
+	}
+}
+
+// Failure is an error together with the pipeline stage and chunk it happened in.
+type Failure struct {
+	Stage string // e.g. "chunking", "audio", "visual", "summary"
+	Chunk int    // -1 when the failure is not tied to a chunk
+	Err   error
+}
+
+func (f Failure) Error() string {
+	if f.Chunk < 0 {
+		return fmt.Sprintf("%s: %v", f.Stage, f.Err)
+	}
+	return fmt.Sprintf("%s, chunk %d: %v", f.Stage, f.Chunk, f.Err)
+}
+
+func (f Failure) Unwrap() error {
+	return f.Err
+}
+
+// Kind returns the kind of the underlying error.
+func (f Failure) Kind() Kind {
+	return KindOf(f.Err)
+}
+
+// Collector gathers failures from concurrent goroutines. Add never blocks on a reader.
+type Collector struct {
+	mu       sync.Mutex
+	failures []Failure
+}
+
+// Add records err for stage and chunk (-1 for none). A nil err is ignored.
+func (c *Collector) Add(stage string, chunk int, err error) {
+	if err == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.failures = append(c.failures, Failure{Stage: stage, Chunk: chunk, Err: err})
+}
+
+// Failures returns a copy of the failures recorded so far.
+func (c *Collector) Failures() []Failure {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]Failure(nil), c.failures...)
+}
@@ -0,0 +1,430 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/utkarsh-cpu/videoSummaryGo/cache"
+	"github.com/utkarsh-cpu/videoSummaryGo/config"
+	"github.com/utkarsh-cpu/videoSummaryGo/llm"
+	"github.com/utkarsh-cpu/videoSummaryGo/output"
+	"github.com/utkarsh-cpu/videoSummaryGo/pipeline"
+	"github.com/utkarsh-cpu/videoSummaryGo/report"
+	"github.com/utkarsh-cpu/videoSummaryGo/retry"
+	"github.com/utkarsh-cpu/videoSummaryGo/secret"
+	"github.com/utkarsh-cpu/videoSummaryGo/subtitle"
+	"github.com/utkarsh-cpu/videoSummaryGo/visual"
+)
+
+// Defaults for the command-line flags.
+const (
+	defaultModel            = "gemini-2.0-flash"
+	defaultChunkDuration    = 60
+	defaultWhisperCLIPath   = "./whisper.cpp/build/bin/whisper-cli"
+	defaultWhisperModelPath = "./whisper.cpp/models/ggml-medium.en.bin"
+	defaultWhisperThreads   = 4
+	defaultWhisperLanguage  = "en"
+)
+
+// configFlags choose the config file and profile layered below the other flags.
+type configFlags struct {
+	path    string
+	profile string
+}
+
+func addConfigFlags(fs *flag.FlagSet) *configFlags {
+	f := &configFlags{}
+	fs.StringVar(&f.path, "config", "", "YAML config file (default $VIDEOSUMMARY_CONFIG or <user config dir>/videoSummaryGo/config.yaml)")
+	fs.StringVar(&f.profile, "profile", "", "profile of the config file to apply on top of its defaults (default $VIDEOSUMMARY_PROFILE or the file's profile key)")
+	return f
+}
+
+// overrides maps a flag to the flag it replaces: when it is given on the command line, the other
+// is not filled from the config file or environment.
+var overrides = map[string]string{
+	"api-key-file": "api-key",
+}
+
+// parse parses args into fs, then fills every flag not given on the command line from, in order
+// of precedence, the environment, the selected profile and the config file defaults.
+func (f *configFlags) parse(fs *flag.FlagSet, args []string) error {
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	settings, _, _, err := f.settings()
+	if err != nil {
+		return err
+	}
+	given := map[string]bool{}
+	fs.Visit(func(fl *flag.Flag) {
+		given[fl.Name] = true
+		if replaced, ok := overrides[fl.Name]; ok {
+			given[replaced] = true
+		}
+		if config.IsSecret(fl.Name) {
+			log.Printf("Warning: --%s is visible to other users in ps output and shell history; use $GEMINI_API_KEY or --api-key-file instead.\n", fl.Name)
+		}
+	})
+	for name, value := range settings.Values() {
+		if given[name] || fs.Lookup(name) == nil {
+			continue
+		}
+		if err := fs.Set(name, value); err != nil {
+			return fmt.Errorf("invalid setting for --%s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// settings merges the config file defaults, the selected profile and the environment, and
+// returns them with the config file ("" if none) and profile ("" if none) used.
+func (f *configFlags) settings() (config.Settings, string, string, error) {
+	var settings config.Settings
+	path := config.Find(f.path)
+	profile := f.profile
+	if profile == "" {
+		profile = os.Getenv(config.EnvProfile)
+	}
+	if path != "" {
+		file, err := config.Load(path)
+		if err != nil {
+			return settings, path, profile, err
+		}
+		if profile == "" {
+			profile = file.Profile
+		}
+		if settings, err = file.Settings(profile); err != nil {
+			return settings, path, profile, fmt.Errorf("%s: %w", path, err)
+		}
+	} else if profile != "" {
+		return settings, "", profile, fmt.Errorf("profile %q selected but no config file found (use --config or $%s)", profile, config.EnvConfig)
+	}
+	env, err := config.FromEnv(os.Getenv)
+	if err != nil {
+		return settings, path, profile, err
+	}
+	settings.Merge(env)
+	return settings, path, profile, nil
+}
+
+// llmFlags select and configure the LLM backend.
+type llmFlags struct {
+	model      string
+	apiKey     string
+	apiKeyFile string
+	baseURL    string
+	attempts   int
+	maxDelay   time.Duration
+	polling    llm.MediaPolling
+}
+
+// apiKeyEnv lists the environment variables each backend's API key is read from when no other
+// source gives one.
+var apiKeyEnv = map[string][]string{
+	"gemini": {"GEMINI_API_KEY", "GOOGLE_API_KEY"},
+	"openai": {"OPENAI_API_KEY"},
+}
+
+func addLLMFlags(fs *flag.FlagSet) *llmFlags {
+	f := &llmFlags{}
+	fs.StringVar(&f.model, "model", defaultModel, `LLM as "[backend:]model"; backend is gemini (default), openai or ollama`)
+	fs.StringVar(&f.apiKey, "api-key", "", "LLM API key; prefer --api-key-file or $GEMINI_API_KEY, $GOOGLE_API_KEY or $OPENAI_API_KEY, which stay out of ps output and shell history. Keys of at least "+strconv.Itoa(secret.MinLength)+" characters are masked in logs")
+	fs.StringVar(&f.apiKeyFile, "api-key-file", "", `read the LLM API key from the first line of this file, which must not be accessible by other users, or from standard input with "-"`)
+	fs.StringVar(&f.baseURL, "base-url", "", "server URL for the openai and ollama backends (default $OPENAI_BASE_URL or $OLLAMA_HOST)")
+	fs.IntVar(&f.attempts, "llm-attempts", retry.DefaultMaxAttempts, "attempts per LLM call or upload; only rate limits, server errors and timeouts are retried")
+	fs.DurationVar(&f.maxDelay, "llm-max-delay", retry.DefaultMaxDelay, "longest wait between LLM retries, including waits requested by the server")
+	fs.DurationVar(&f.polling.Timeout, "upload-timeout", llm.DefaultMediaActivationTimeout, "how long an uploaded chunk may take to be processed by Gemini before the upload fails")
+	fs.DurationVar(&f.polling.Interval, "upload-poll-interval", llm.DefaultMediaPollInterval, "first wait between checks of an uploaded chunk's state; doubles after each check")
+	fs.DurationVar(&f.polling.MaxInterval, "upload-max-poll-interval", llm.DefaultMediaMaxPollInterval, "longest wait between checks of an uploaded chunk's state")
+	return f
+}
+
+// options returns the llm.Options selected by the flags.
+func (f *llmFlags) options() (llm.Options, error) {
+	backend, model := llm.ParseModel(f.model)
+	apiKey, err := f.key(backend)
+	if err != nil {
+		return llm.Options{}, err
+	}
+	baseURL := f.baseURL
+	if baseURL == "" {
+		baseURL = os.Getenv("OPENAI_BASE_URL")
+		if backend == "ollama" {
+			baseURL = os.Getenv("OLLAMA_HOST")
+		}
+	}
+	return llm.Options{
+		Backend:      backend,
+		Model:        model,
+		APIKey:       apiKey,
+		BaseURL:      baseURL,
+		MediaPolling: f.polling,
+		Retry:        retry.Policy{MaxAttempts: f.attempts, MaxDelay: f.maxDelay},
+		Logger:       progressLog,
+	}, nil
+}
+
+// key returns the API key for backend from the first source that has one: --api-key (or the
+// api_key setting), --api-key-file, then the backend's environment variables. It returns "" when
+// there is none, which is fine for servers without authentication.
+func (f *llmFlags) key(backend string) (string, error) {
+	if f.apiKey != "" {
+		return f.apiKey, nil
+	}
+	if f.apiKeyFile != "" {
+		return config.ReadSecret(f.apiKeyFile)
+	}
+	for _, env := range apiKeyEnv[backend] {
+		if key := os.Getenv(env); key != "" {
+			return key, nil
+		}
+	}
+	return "", nil
+}
+
+func (f *llmFlags) apply(cfg *pipeline.Config) error {
+	opts, err := f.options()
+	if err != nil {
+		return err
+	}
+	cfg.LLMBackend = opts.Backend
+	cfg.LLM = opts.Model
+	cfg.APIKey = opts.APIKey
+	cfg.LLMBaseURL = opts.BaseURL
+	cfg.MediaPolling = opts.MediaPolling
+	cfg.Retry = opts.Retry
+	return nil
+}
+
+// whisperFlags configure the whisper-cli audio transcriber.
+type whisperFlags struct {
+	cliPath   string
+	modelPath string
+	threads   int
+	language  string
+}
+
+func addWhisperFlags(fs *flag.FlagSet) *whisperFlags {
+	f := &whisperFlags{}
+	fs.StringVar(&f.cliPath, "whisper-cli", defaultWhisperCLIPath, "path to the whisper.cpp whisper-cli binary")
+	fs.StringVar(&f.modelPath, "whisper-model", defaultWhisperModelPath, "path to the whisper ggml model")
+	fs.IntVar(&f.threads, "whisper-threads", defaultWhisperThreads, "threads per whisper-cli process")
+	fs.StringVar(&f.language, "language", defaultWhisperLanguage, `spoken language passed to whisper ("auto" to detect)`)
+	return f
+}
+
+func (f *whisperFlags) apply(cfg *pipeline.Config) {
+	cfg.WhisperCLIPath = f.cliPath
+	cfg.WhisperModelPath = f.modelPath
+	cfg.WhisperThreads = f.threads
+	cfg.WhisperLanguage = f.language
+}
+
+// chunkFlags control chunking and concurrency.
+type chunkFlags struct {
+	duration           int
+	silenceWindow      time.Duration
+	workers            int
+	whisperConcurrency int
+	visualConcurrency  int
+}
+
+func addChunkFlags(fs *flag.FlagSet) *chunkFlags {
+	f := &chunkFlags{}
+	fs.IntVar(&f.duration, "chunk-duration", defaultChunkDuration, "chunk length in seconds")
+	fs.DurationVar(&f.silenceWindow, "silence-window", 0, "move each cut to the nearest pause in the audio within this distance, e.g. 5s, so words are not cut in half (default: cut at fixed intervals)")
+	fs.IntVar(&f.workers, "workers", pipeline.DefaultChunkWorkers, "chunks processed at once")
+	fs.IntVar(&f.whisperConcurrency, "whisper-concurrency", 0, "concurrent whisper-cli processes (default: CPUs / whisper threads)")
+	fs.IntVar(&f.visualConcurrency, "visual-concurrency", pipeline.DefaultVisualConcurrency, "concurrent visual transcriptions (LLM uploads)")
+	return f
+}
+
+func (f *chunkFlags) apply(cfg *pipeline.Config) error {
+	if f.duration <= 0 {
+		return fmt.Errorf("invalid chunk duration %d: must be positive", f.duration)
+	}
+	if f.silenceWindow < 0 || f.silenceWindow >= time.Duration(f.duration)*time.Second {
+		return fmt.Errorf("invalid silence window %s: must be shorter than the chunk duration", f.silenceWindow)
+	}
+	cfg.ChunkDuration = f.duration
+	cfg.SilenceWindow = f.silenceWindow
+	cfg.ChunkWorkers = f.workers
+	cfg.WhisperConcurrency = f.whisperConcurrency
+	cfg.VisualConcurrency = f.visualConcurrency
+	return nil
+}
+
+// outputFlags choose where outputs and checkpoints go.
+type outputFlags struct {
+	dir    string
+	resume bool
+	// command names the subcommand, which keeps its own checkpoints unless it is run.
+	command string
+}
+
+func addOutputFlags(fs *flag.FlagSet) *outputFlags {
+	f := &outputFlags{command: fs.Name()}
+	fs.StringVar(&f.dir, "output-dir", "", "write each video's outputs to its own folder below this directory, mirroring the input folder tree (default: all files in the current directory)")
+	manifest := "<name>_manifest.json"
+	if f.command != "run" {
+		manifest = "<name>_" + f.command + "_manifest.json"
+	}
+	fs.BoolVar(&f.resume, "resume", false, "skip chunks and videos already completed by an earlier run of this command, using the "+manifest+" checkpoints")
+	return f
+}
+
+// addReportTemplateFlag adds --report-template and returns its value.
+func addReportTemplateFlag(fs *flag.FlagSet) *string {
+	return fs.String("report-template", "", "render the Markdown and PDF reports with this Go template instead of the built-in report/templates/report.md.tmpl")
+}
+
+// loadReportTemplate parses the report template at path, or returns nil for the built-in one
+// when path is empty.
+func loadReportTemplate(path string) (*template.Template, error) {
+	if path == "" {
+		return nil, nil
+	}
+	return report.ParseTemplate(path)
+}
+
+// addSubtitleFlags adds the flags that shape subtitle cues and returns the options they set.
+func addSubtitleFlags(fs *flag.FlagSet) *subtitle.Options {
+	opts := &subtitle.Options{}
+	fs.IntVar(&opts.MaxLineLength, "subtitle-max-line", subtitle.DefaultMaxLineLength, "characters per subtitle line; longer text is wrapped or split into several cues")
+	fs.DurationVar(&opts.MaxCueDuration, "subtitle-max-cue", subtitle.DefaultMaxCueDuration, "longest time a subtitle cue stays on screen; longer segments are split into several cues")
+	return opts
+}
+
+// layout returns the output layout for videos found under input and sets up checkpointing in cfg.
+func (f *outputFlags) layout(cfg *pipeline.Config, input string) *output.Layout {
+	layout := output.NewFlatLayout(".")
+	if f.dir != "" {
+		layout = output.NewLayout(f.dir, input)
+	}
+	cfg.Resume = f.resume
+	cfg.ManifestPath = layout.ManifestPath
+	if f.command != "run" {
+		cfg.ManifestPath = layout.CommandManifestPath(f.command)
+	}
+	return layout
+}
+
+// cacheFlags configure the transcription and LLM response cache.
+type cacheFlags struct {
+	enabled bool
+	dir     string
+	maxMB   int64
+	only    bool
+}
+
+func addCacheFlags(fs *flag.FlagSet) *cacheFlags {
+	f := &cacheFlags{}
+	fs.BoolVar(&f.enabled, "cache", false, "reuse transcriptions and LLM responses from the local cache and store new ones in it")
+	fs.StringVar(&f.dir, "cache-dir", "", "cache directory, implies --cache (default: <user cache dir>/videoSummaryGo)")
+	fs.Int64Var(&f.maxMB, "cache-max-mb", cache.DefaultMaxBytes>>20, "cache size limit in MiB; least recently used entries are evicted beyond it")
+	fs.BoolVar(&f.only, "cache-only", false, "never call whisper or the LLM: serve everything from the cache and fail what is not cached, implies --cache")
+	return f
+}
+
+// store opens the cache, or returns nil when caching is off.
+func (f *cacheFlags) store() (*cache.Store, error) {
+	if !f.enabled && !f.only && f.dir == "" {
+		return nil, nil
+	}
+	store, err := cache.NewStore(f.dir, f.maxMB<<20)
+	if err != nil {
+		return nil, err
+	}
+	store.CacheOnly = f.only
+	return store, nil
+}
+
+// ocrFlags select and configure the visual transcription backend.
+type ocrFlags struct {
+	backend  string
+	language string
+}
+
+func addOCRFlags(fs *flag.FlagSet) *ocrFlags {
+	f := &ocrFlags{}
+	fs.StringVar(&f.backend, "visual", "auto", "visual transcription: auto (LLM, falling back to tesseract) or tesseract (offline OCR only)")
+	fs.StringVar(&f.language, "ocr-language", "", `tesseract language packs, e.g. "eng+deu" (default: tesseract's)`)
+	return f
+}
+
+func (f *ocrFlags) apply(cfg *pipeline.Config) error {
+	cfg.OCRLanguage = f.language
+	switch f.backend {
+	case "auto":
+	case "tesseract":
+		cfg.VisualTranscriber = &visual.TesseractFramesTranscriber{Language: f.language}
+	default:
+		return fmt.Errorf("unknown visual backend %q: use auto or tesseract", f.backend)
+	}
+	return nil
+}
+
+// promptFlags replace the built-in LLM instructions.
+type promptFlags struct {
+	summary string
+	visual  string
+}
+
+func addPromptFlags(fs *flag.FlagSet) *promptFlags {
+	f := &promptFlags{}
+	fs.StringVar(&f.summary, "summary-prompt", "", "instructions placed before the transcripts in the summary prompt (default: the built-in lecture summary)")
+	fs.StringVar(&f.visual, "visual-prompt", "", "instructions sent with each chunk for LLM visual transcription (default: transcribe the on-screen text)")
+	return f
+}
+
+func (f *promptFlags) apply(cfg *pipeline.Config) {
+	cfg.SummaryPrompt = f.summary
+	cfg.VisualPrompt = f.visual
+}
+
+// Output formats selectable with --formats.
+const (
+	formatText   = "txt" // summary and transcripts
+	formatJSON   = "json"
+	formatReport = "md"
+	formatPDF    = "pdf" // implies md
+	formatSRT    = "srt"
+	formatVTT    = "vtt"
+)
+
+var allFormats = []string{formatText, formatJSON, formatReport, formatPDF, formatSRT, formatVTT}
+
+// defaultFormats leaves out the PDF: the Markdown report is the canonical one and the PDF fonts
+// only cover Windows-1252.
+var defaultFormats = []string{formatText, formatJSON, formatReport, formatSRT, formatVTT}
+
+// formatsFlag selects the output files written for each video.
+type formatsFlag struct {
+	list string
+}
+
+func addFormatsFlag(fs *flag.FlagSet) *formatsFlag {
+	f := &formatsFlag{}
+	fs.StringVar(&f.list, "formats", strings.Join(defaultFormats, ","), "comma-separated outputs to write: txt (summary and transcripts), json, md (report), pdf (report as PDF, Windows-1252 characters only, implies md), srt, vtt")
+	return f
+}
+
+// set returns the selected formats.
+func (f *formatsFlag) set() (map[string]bool, error) {
+	formats := map[string]bool{}
+	for _, format := range config.SplitList(f.list) {
+		switch format {
+		case formatText, formatJSON, formatReport, formatPDF, formatSRT, formatVTT:
+			formats[format] = true
+		default:
+			return nil, fmt.Errorf("unknown output format %q: use %s", format, strings.Join(allFormats, ", "))
+		}
+	}
+	return formats, nil
+}
@@ -0,0 +1,126 @@
+package llm
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/generative-ai-go/genai"
+	"google.golang.org/api/googleapi"
+
+	"github.com/utkarsh-cpu/videoSummaryGo/failure"
+	"github.com/utkarsh-cpu/videoSummaryGo/secret"
+)
+
+// APIError is an error response from an LLM provider's API.
+type APIError struct {
+	StatusCode int
+	Message    string
+	// RetryAfter is the provider's hint for when to retry, zero if it gave none.
+	RetryAfter time.Duration
+	Err        error // underlying client error, if any
+}
+
+func (e *APIError) Error() string {
+	return e.Message
+}
+
+func (e *APIError) Unwrap() error {
+	return e.Err
+}
+
+// HTTPStatus returns the response status code.
+func (e *APIError) HTTPStatus() int {
+	return e.StatusCode
+}
+
+// RetryDelay returns the provider's retry hint.
+func (e *APIError) RetryDelay() time.Duration {
+	return e.RetryAfter
+}
+
+// statusKind classifies an HTTP error status; fallback is used for statuses without a specific kind.
+func statusKind(code int, fallback failure.Kind) failure.Kind {
+	switch code {
+	case http.StatusTooManyRequests:
+		return failure.LLMQuota
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return failure.LLMAuth
+	default:
+		return fallback
+	}
+}
+
+// parseRetryAfter parses a Retry-After header given in seconds or as an HTTP date.
+func parseRetryAfter(value string) time.Duration {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if t, err := http.ParseTime(value); err == nil {
+		return max(time.Until(t), 0)
+	}
+	return 0
+}
+
+// httpError builds the error for a non-2xx response from an HTTP backend and closes its body.
+func httpError(what string, resp *http.Response) error {
+	defer resp.Body.Close()
+	msg, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	return failure.Wrap(statusKind(resp.StatusCode, failure.LLM), &APIError{
+		StatusCode: resp.StatusCode,
+		Message:    secret.Redact(fmt.Sprintf("%s returned %s: %s", what, resp.Status, strings.TrimSpace(string(msg)))),
+		RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After")),
+	})
+}
+
+// geminiError classifies an error returned by the genai client; fallback is the kind of errors
+// that are not more specific, e.g. failure.Upload for uploads. Its message is redacted, since
+// request URLs in transport errors carry the API key.
+func geminiError(err error, fallback failure.Kind) error {
+	if err == nil {
+		return nil
+	}
+	err = secret.Error(err)
+	var blocked *genai.BlockedError
+	if errors.As(err, &blocked) {
+		return failure.Wrap(failure.LLMBlocked, err)
+	}
+	var gErr *googleapi.Error
+	if errors.As(err, &gErr) {
+		kind := statusKind(gErr.Code, fallback)
+		// Gemini reports an invalid key as 400 INVALID_ARGUMENT.
+		if strings.Contains(gErr.Message, "API key not valid") || strings.Contains(gErr.Body, "API_KEY_INVALID") {
+			kind = failure.LLMAuth
+		}
+		retryAfter := parseRetryAfter(gErr.Header.Get("Retry-After"))
+		if retryAfter == 0 {
+			retryAfter = geminiRetryDelay(gErr.Details)
+		}
+		return failure.Wrap(kind, &APIError{StatusCode: gErr.Code, Message: err.Error(), RetryAfter: retryAfter, Err: err})
+	}
+	return failure.Wrap(fallback, err)
+}
+
+// geminiRetryDelay returns the retryDelay of a google.rpc.RetryInfo error detail, e.g. "37s".
+func geminiRetryDelay(details []any) time.Duration {
+	for _, d := range details {
+		m, ok := d.(map[string]any)
+		if !ok || !strings.HasSuffix(fmt.Sprint(m["@type"]), "google.rpc.RetryInfo") {
+			continue
+		}
+		if s, ok := m["retryDelay"].(string); ok {
+			if delay, err := time.ParseDuration(s); err == nil {
+				return delay
+			}
+		}
+	}
+	return 0
+}
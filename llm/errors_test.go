@@ -0,0 +1,89 @@
+package llm
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/utkarsh-cpu/videoSummaryGo/failure"
+	"github.com/utkarsh-cpu/videoSummaryGo/retry"
+)
+
+func TestStatusKind(t *testing.T) {
+	tests := []struct {
+		code     int
+		fallback failure.Kind
+		want     failure.Kind
+	}{
+		{http.StatusTooManyRequests, failure.LLM, failure.LLMQuota},
+		{http.StatusUnauthorized, failure.LLM, failure.LLMAuth},
+		{http.StatusForbidden, failure.Upload, failure.LLMAuth},
+		{http.StatusBadRequest, failure.LLM, failure.LLM},
+		{http.StatusInternalServerError, failure.Upload, failure.Upload},
+	}
+	for _, tt := range tests {
+		if got := statusKind(tt.code, tt.fallback); got != tt.want {
+			t.Errorf("statusKind(%d, %v) = %v, want %v", tt.code, tt.fallback, got, tt.want)
+		}
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	tests := []struct {
+		value string
+		want  time.Duration
+	}{
+		{"", 0},
+		{"30", 30 * time.Second},
+		{" 7 ", 7 * time.Second},
+		{"0", 0},
+		{"-5", 0},
+		{"soon", 0},
+		{"Wed, 21 Oct 2015 07:28:00 GMT", 0}, // in the past
+	}
+	for _, tt := range tests {
+		if got := parseRetryAfter(tt.value); got != tt.want {
+			t.Errorf("parseRetryAfter(%q) = %v, want %v", tt.value, got, tt.want)
+		}
+	}
+
+	future := time.Now().Add(time.Minute).UTC().Format(http.TimeFormat)
+	if got := parseRetryAfter(future); got <= 50*time.Second || got > time.Minute {
+		t.Errorf("parseRetryAfter(%q) = %v, want about a minute", future, got)
+	}
+}
+
+func TestHTTPError(t *testing.T) {
+	tests := []struct {
+		status     int
+		retryAfter string
+		wantKind   failure.Kind
+		wantRetry  bool
+		wantDelay  time.Duration
+	}{
+		{http.StatusTooManyRequests, "12", failure.LLMQuota, true, 12 * time.Second},
+		{http.StatusUnauthorized, "", failure.LLMAuth, false, 0},
+		{http.StatusBadRequest, "", failure.LLM, false, 0},
+		{http.StatusServiceUnavailable, "3", failure.LLM, true, 3 * time.Second},
+	}
+	for _, tt := range tests {
+		resp := &http.Response{
+			StatusCode: tt.status,
+			Status:     http.StatusText(tt.status),
+			Header:     http.Header{"Retry-After": {tt.retryAfter}},
+			Body:       io.NopCloser(strings.NewReader("details")),
+		}
+		err := httpError("test", resp)
+		if got := failure.KindOf(err); got != tt.wantKind {
+			t.Errorf("%d: kind = %v, want %v", tt.status, got, tt.wantKind)
+		}
+		if got := retry.Retryable(err); got != tt.wantRetry {
+			t.Errorf("%d: Retryable = %v, want %v", tt.status, got, tt.wantRetry)
+		}
+		if got := (retry.Policy{Jitter: -1}).Delay(1, err); tt.wantDelay > 0 && got != tt.wantDelay {
+			t.Errorf("%d: Delay = %v, want the Retry-After hint %v", tt.status, got, tt.wantDelay)
+		}
+	}
+}
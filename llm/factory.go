@@ -0,0 +1,64 @@
+package llm
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/utkarsh-cpu/videoSummaryGo/progress"
+	"github.com/utkarsh-cpu/videoSummaryGo/retry"
+	"github.com/utkarsh-cpu/videoSummaryGo/secret"
+)
+
+// Options selects and configures an LLM backend.
+type Options struct {
+	Backend string // "gemini" (default), "openai" or "ollama"
+	Model   string
+	APIKey  string
+	BaseURL string // only used by HTTP backends
+
+	// MediaPolling controls how long uploads may take to become usable (Gemini only).
+	MediaPolling MediaPolling
+	// Retry controls how failed calls are retried; the zero value uses the retry package defaults.
+	Retry retry.Policy
+	// Logger receives progress messages such as retries and upload waits; nil discards them.
+	Logger progress.Logger
+}
+
+// New creates the LLM backend named by opts.Backend, wrapped to retry transient errors. The API
+// key is registered with the secret package so that it is redacted from logs and errors.
+func New(ctx context.Context, opts Options) (LLM, error) {
+	secret.Register(opts.APIKey)
+	ctx = progress.NewContext(ctx, opts.Logger)
+	var model LLM
+	switch opts.Backend {
+	case "", "gemini":
+		gemini, err := NewGemini(ctx, opts.Model, opts.APIKey)
+		if err != nil {
+			return nil, err
+		}
+		gemini.Polling = opts.MediaPolling
+		model = gemini
+	case "openai":
+		model = NewOpenAI(opts.BaseURL, opts.Model, opts.APIKey)
+	case "ollama":
+		model = NewOllama(opts.BaseURL, opts.Model)
+	default:
+		return nil, fmt.Errorf("unknown LLM backend %q", opts.Backend)
+	}
+	retrying := WithRetry(model, opts.Retry)
+	retrying.Logger = opts.Logger
+	return retrying, nil
+}
+
+// ParseModel splits a "backend:model" string such as "openai:llama3". A string without a
+// known backend prefix is a Gemini model name.
+func ParseModel(s string) (backend string, model string) {
+	if b, m, ok := strings.Cut(s, ":"); ok {
+		switch b {
+		case "gemini", "openai", "ollama":
+			return b, m
+		}
+	}
+	return "gemini", s
+}
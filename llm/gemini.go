@@ -0,0 +1,198 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/generative-ai-go/genai"
+	"google.golang.org/api/iterator"
+	"google.golang.org/api/option"
+
+	"github.com/utkarsh-cpu/videoSummaryGo/failure"
+	"github.com/utkarsh-cpu/videoSummaryGo/progress"
+	"github.com/utkarsh-cpu/videoSummaryGo/secret"
+)
+
+// Gemini is the LLM implementation backed by the Google Gemini API.
+type Gemini struct {
+	client *genai.Client
+	model  *genai.GenerativeModel
+	name   string
+
+	// Polling controls how UploadMedia waits for uploaded files to become ACTIVE.
+	Polling MediaPolling
+}
+
+// NewGemini creates a Gemini client for the named model.
+func NewGemini(ctx context.Context, model string, apiKey string) (*Gemini, error) {
+	client, err := genai.NewClient(ctx, option.WithAPIKey(apiKey))
+	if err != nil {
+		return nil, fmt.Errorf("error creating Gemini client: %w", secret.Error(err))
+	}
+	progress.Printf(ctx, "LLM API setup complete.")
+	return &Gemini{client: client, model: client.GenerativeModel(model), name: model}, nil
+}
+
+// Name returns "gemini".
+func (g *Gemini) Name() string {
+	return "gemini"
+}
+
+// Model returns the Gemini model name.
+func (g *Gemini) Model() string {
+	return g.name
+}
+
+// Client returns the underlying genai client for Gemini-specific calls.
+func (g *Gemini) Client() *genai.Client {
+	return g.client
+}
+
+// Generate sends parts to the model and returns the concatenated text of all candidates.
+func (g *Gemini) Generate(ctx context.Context, parts []Part) (*Response, error) {
+	resp, err := g.model.GenerateContent(ctx, geminiParts(parts)...)
+	if err != nil {
+		return nil, geminiError(err, failure.LLM)
+	}
+	return geminiResponse(resp), nil
+}
+
+// GenerateStream streams the response, calling fn with each text fragment.
+func (g *Gemini) GenerateStream(ctx context.Context, parts []Part, fn func(text string) error) (*Response, error) {
+	iter := g.model.GenerateContentStream(ctx, geminiParts(parts)...)
+	var full strings.Builder
+	var finishReason string
+	for {
+		resp, err := iter.Next()
+		if errors.Is(err, iterator.Done) {
+			break
+		}
+		if err != nil {
+			return nil, geminiError(err, failure.LLM)
+		}
+		chunk := geminiResponse(resp)
+		if chunk.FinishReason != "" {
+			finishReason = chunk.FinishReason
+		}
+		full.WriteString(chunk.Text)
+		if fn != nil && chunk.Text != "" {
+			if err := fn(chunk.Text); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return &Response{Text: full.String(), FinishReason: finishReason}, nil
+}
+
+// UploadMedia uploads path with the Gemini File API and waits until the file is ACTIVE.
+// The upload is deleted again if it fails processing or does not become active in time.
+func (g *Gemini) UploadMedia(ctx context.Context, path string) (*Media, error) {
+	file, err := g.client.UploadFileFromPath(ctx, path, nil)
+	if err != nil {
+		return nil, geminiError(err, failure.Upload)
+	}
+	file, err = waitActive(ctx, g.Polling, file, g.client.GetFile)
+	if err != nil {
+		g.client.DeleteFile(context.WithoutCancel(ctx), file.Name)
+		return nil, failure.Wrap(failure.Upload, err)
+	}
+	return &Media{Name: file.Name, URI: file.URI, MIMEType: file.MIMEType}, nil
+}
+
+// waitActive polls getFile with exponential backoff until file leaves the PROCESSING state.
+func waitActive(ctx context.Context, polling MediaPolling, file *genai.File, getFile func(ctx context.Context, name string) (*genai.File, error)) (*genai.File, error) {
+	polling = polling.withDefaults()
+	deadline := time.Now().Add(polling.Timeout)
+	interval := polling.Interval
+	if file.State != genai.FileStateActive {
+		progress.Printf(ctx, "Waiting for uploaded file %s to become active...", file.Name)
+	}
+	for file.State == genai.FileStateProcessing || file.State == genai.FileStateUnspecified {
+		if time.Now().After(deadline) {
+			return file, fmt.Errorf("media %s still %s after %v: %w", file.Name, file.State, polling.Timeout, ErrMediaNotReady)
+		}
+		select {
+		case <-ctx.Done():
+			return file, ctx.Err()
+		case <-time.After(interval):
+		}
+		interval = min(interval*2, polling.MaxInterval)
+
+		latest, err := getFile(ctx, file.Name)
+		if err != nil {
+			return file, fmt.Errorf("error getting state of media %s: %w", file.Name, geminiError(err, failure.Upload))
+		}
+		file = latest
+	}
+	if file.State != genai.FileStateActive {
+		perr := &MediaProcessingError{Name: file.Name, State: file.State.String()}
+		if file.Error != nil {
+			perr.Message = file.Error.Error()
+		}
+		return file, perr
+	}
+	return file, nil
+}
+
+// DeleteMedia deletes an uploaded file.
+func (g *Gemini) DeleteMedia(ctx context.Context, m *Media) error {
+	return g.client.DeleteFile(ctx, m.Name)
+}
+
+// CountTokens returns the number of input tokens parts would use.
+func (g *Gemini) CountTokens(ctx context.Context, parts []Part) (int, error) {
+	resp, err := g.model.CountTokens(ctx, geminiParts(parts)...)
+	if err != nil {
+		return 0, geminiError(err, failure.LLM)
+	}
+	return int(resp.TotalTokens), nil
+}
+
+// Ping fetches the model's metadata, which needs a valid API key and model name.
+func (g *Gemini) Ping(ctx context.Context) error {
+	_, err := g.model.Info(ctx)
+	return geminiError(err, failure.LLM)
+}
+
+// Close releases the underlying client.
+func (g *Gemini) Close() error {
+	return g.client.Close()
+}
+
+// geminiParts converts provider-neutral parts to genai parts.
+func geminiParts(parts []Part) []genai.Part {
+	var out []genai.Part
+	for _, p := range parts {
+		switch {
+		case p.Media != nil:
+			out = append(out, genai.FileData{URI: p.Media.URI, MIMEType: p.Media.MIMEType})
+		case p.Data != nil:
+			out = append(out, genai.Blob{MIMEType: p.MIMEType, Data: p.Data})
+		default:
+			out = append(out, genai.Text(p.Text))
+		}
+	}
+	return out
+}
+
+// geminiResponse collects the text parts of every candidate.
+func geminiResponse(resp *genai.GenerateContentResponse) *Response {
+	var text strings.Builder
+	var finishReason string
+	for _, c := range resp.Candidates {
+		if c.FinishReason != genai.FinishReasonUnspecified {
+			finishReason = c.FinishReason.String()
+		}
+		if c.Content != nil {
+			for _, part := range c.Content.Parts {
+				if t, ok := part.(genai.Text); ok {
+					text.WriteString(string(t))
+				}
+			}
+		}
+	}
+	return &Response{Text: text.String(), FinishReason: finishReason}
+}
@@ -0,0 +1,65 @@
+package llm
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrNotSupported is returned by backends that do not implement an optional operation,
+// such as media upload on a server that only accepts inline images.
+var ErrNotSupported = errors.New("operation not supported by this LLM backend")
+
+// Media is a file uploaded to the provider for use in prompts.
+type Media struct {
+	Name     string // provider identifier used for deletion
+	URI      string // reference used in prompts
+	MIMEType string
+}
+
+// Part is one piece of a prompt. Exactly one of Text, Data or Media is set.
+type Part struct {
+	Text     string
+	MIMEType string // MIME type of Data
+	Data     []byte // inline media such as a JPEG frame
+	Media    *Media // previously uploaded media
+}
+
+// Text returns a text prompt part.
+func Text(s string) Part {
+	return Part{Text: s}
+}
+
+// Image returns an inline image prompt part.
+func Image(mimeType string, data []byte) Part {
+	return Part{MIMEType: mimeType, Data: data}
+}
+
+// MediaPart returns a prompt part referencing uploaded media.
+func MediaPart(m *Media) Part {
+	return Part{Media: m}
+}
+
+// Response is a generated completion.
+type Response struct {
+	Text         string
+	FinishReason string
+}
+
+// LLM is a provider-neutral generative model client.
+type LLM interface {
+	// Name identifies the backend, e.g. "gemini".
+	Name() string
+	// Model returns the model name requests are sent to.
+	Model() string
+	Generate(ctx context.Context, parts []Part) (*Response, error)
+	// GenerateStream calls fn with each text fragment as it arrives and returns the full response.
+	GenerateStream(ctx context.Context, parts []Part, fn func(text string) error) (*Response, error)
+	// UploadMedia uploads a local file so it can be referenced with MediaPart.
+	UploadMedia(ctx context.Context, path string) (*Media, error)
+	DeleteMedia(ctx context.Context, m *Media) error
+	CountTokens(ctx context.Context, parts []Part) (int, error)
+	// Ping checks that the backend is reachable and accepts the credentials and model, without
+	// generating anything.
+	Ping(ctx context.Context) error
+	Close() error
+}
@@ -0,0 +1,52 @@
+package llm
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Defaults for waiting on uploaded media to become usable.
+const (
+	DefaultMediaActivationTimeout = 10 * time.Minute
+	DefaultMediaPollInterval      = 2 * time.Second
+	DefaultMediaMaxPollInterval   = 30 * time.Second
+)
+
+// ErrMediaNotReady is wrapped by UploadMedia when the file is still processing at the activation timeout.
+var ErrMediaNotReady = errors.New("uploaded media did not become active in time")
+
+// MediaProcessingError is returned by UploadMedia when the provider reports that it failed to process the file.
+type MediaProcessingError struct {
+	Name    string // provider identifier of the upload
+	State   string
+	Message string // provider error message, if any
+}
+
+func (e *MediaProcessingError) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("media %s processing failed with state %s", e.Name, e.State)
+	}
+	return fmt.Sprintf("media %s processing failed with state %s: %s", e.Name, e.State, e.Message)
+}
+
+// MediaPolling controls how UploadMedia waits for a file to become active.
+type MediaPolling struct {
+	Timeout     time.Duration // total time to wait; DefaultMediaActivationTimeout when zero
+	Interval    time.Duration // first delay between polls; DefaultMediaPollInterval when zero
+	MaxInterval time.Duration // cap for the doubling delay; DefaultMediaMaxPollInterval when zero
+}
+
+// withDefaults fills zero fields with the package defaults.
+func (p MediaPolling) withDefaults() MediaPolling {
+	if p.Timeout <= 0 {
+		p.Timeout = DefaultMediaActivationTimeout
+	}
+	if p.Interval <= 0 {
+		p.Interval = DefaultMediaPollInterval
+	}
+	if p.MaxInterval <= 0 {
+		p.MaxInterval = DefaultMediaMaxPollInterval
+	}
+	return p
+}
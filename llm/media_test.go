@@ -0,0 +1,90 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/generative-ai-go/genai"
+)
+
+// fileStates returns a GetFile stand-in that reports states in turn, repeating the last one,
+// and counts the calls in *polls.
+func fileStates(polls *int, states ...genai.FileState) func(ctx context.Context, name string) (*genai.File, error) {
+	return func(ctx context.Context, name string) (*genai.File, error) {
+		state := states[min(*polls, len(states)-1)]
+		*polls++
+		return &genai.File{Name: name, URI: "https://example.com/" + name, State: state}, nil
+	}
+}
+
+var fastPolling = MediaPolling{Timeout: time.Second, Interval: time.Millisecond, MaxInterval: 4 * time.Millisecond}
+
+func TestWaitActive(t *testing.T) {
+	polls := 0
+	uploaded := &genai.File{Name: "files/abc", State: genai.FileStateProcessing}
+	file, err := waitActive(context.Background(), fastPolling, uploaded, fileStates(&polls, genai.FileStateProcessing, genai.FileStateProcessing, genai.FileStateActive))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if file.State != genai.FileStateActive || polls != 3 {
+		t.Errorf("state %s after %d polls, want ACTIVE after 3", file.State, polls)
+	}
+
+	// An upload that is already active is not polled.
+	polls = 0
+	active := &genai.File{Name: "files/def", State: genai.FileStateActive}
+	if _, err := waitActive(context.Background(), fastPolling, active, fileStates(&polls, genai.FileStateFailed)); err != nil || polls != 0 {
+		t.Errorf("active upload: error %v after %d polls", err, polls)
+	}
+}
+
+func TestWaitActiveFailed(t *testing.T) {
+	polls := 0
+	uploaded := &genai.File{Name: "files/abc", State: genai.FileStateProcessing}
+	_, err := waitActive(context.Background(), fastPolling, uploaded, fileStates(&polls, genai.FileStateProcessing, genai.FileStateFailed))
+	var perr *MediaProcessingError
+	if !errors.As(err, &perr) {
+		t.Fatalf("error = %v, want a *MediaProcessingError", err)
+	}
+	if perr.Name != "files/abc" || perr.State != genai.FileStateFailed.String() {
+		t.Errorf("MediaProcessingError = %+v", perr)
+	}
+}
+
+func TestWaitActiveTimeout(t *testing.T) {
+	polls := 0
+	polling := MediaPolling{Timeout: 20 * time.Millisecond, Interval: time.Millisecond, MaxInterval: 2 * time.Millisecond}
+	uploaded := &genai.File{Name: "files/abc", State: genai.FileStateProcessing}
+	started := time.Now()
+	_, err := waitActive(context.Background(), polling, uploaded, fileStates(&polls, genai.FileStateProcessing))
+	if !errors.Is(err, ErrMediaNotReady) {
+		t.Fatalf("error = %v, want ErrMediaNotReady", err)
+	}
+	if elapsed := time.Since(started); elapsed < polling.Timeout || elapsed > time.Second {
+		t.Errorf("gave up after %s, want just after %s", elapsed, polling.Timeout)
+	}
+	// The interval doubles up to MaxInterval, so a 20ms timeout allows several polls.
+	if polls < 3 {
+		t.Errorf("polled %d times, want at least 3", polls)
+	}
+}
+
+func TestWaitActiveCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	polls := 0
+	uploaded := &genai.File{Name: "files/abc", State: genai.FileStateProcessing}
+	if _, err := waitActive(ctx, fastPolling, uploaded, fileStates(&polls, genai.FileStateProcessing)); !errors.Is(err, context.Canceled) {
+		t.Errorf("error = %v, want context.Canceled", err)
+	}
+}
+
+func TestMediaPollingDefaults(t *testing.T) {
+	got := MediaPolling{Interval: 5 * time.Second}.withDefaults()
+	want := MediaPolling{Timeout: DefaultMediaActivationTimeout, Interval: 5 * time.Second, MaxInterval: DefaultMediaMaxPollInterval}
+	if got != want {
+		t.Errorf("withDefaults() = %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,241 @@
+package llm
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+)
+
+// DefaultOllamaBaseURL is used when Ollama.BaseURL is empty.
+const DefaultOllamaBaseURL = "http://localhost:11434"
+
+// Ollama is the LLM implementation for a local Ollama server. Text-only prompts go to
+// /api/generate; prompts with images go to /api/chat so vision models such as llava see them.
+type Ollama struct {
+	BaseURL    string
+	ModelName  string
+	HTTPClient *http.Client
+}
+
+// NewOllama returns an Ollama client for model at baseURL. Like $OLLAMA_HOST, baseURL may be
+// given as host:port, or just host for the default port, without a scheme.
+func NewOllama(baseURL string, model string) *Ollama {
+	if baseURL == "" {
+		baseURL = DefaultOllamaBaseURL
+	}
+	if !strings.Contains(baseURL, "://") {
+		host, _, _ := strings.Cut(baseURL, "/")
+		if _, _, err := net.SplitHostPort(host); err != nil {
+			baseURL = strings.Replace(baseURL, host, net.JoinHostPort(strings.Trim(host, "[]"), "11434"), 1)
+		}
+		baseURL = "http://" + baseURL
+	}
+	return &Ollama{BaseURL: strings.TrimSuffix(baseURL, "/"), ModelName: model, HTTPClient: http.DefaultClient}
+}
+
+// Name returns "ollama".
+func (o *Ollama) Name() string {
+	return "ollama"
+}
+
+// Model returns the model name sent with each request.
+func (o *Ollama) Model() string {
+	return o.ModelName
+}
+
+type ollamaGenerateRequest struct {
+	Model  string `json:"model"`
+	Prompt string `json:"prompt"`
+	Stream bool   `json:"stream"`
+}
+
+type ollamaMessage struct {
+	Role    string   `json:"role"`
+	Content string   `json:"content"`
+	Images  []string `json:"images,omitempty"` // base64 encoded
+}
+
+type ollamaChatRequest struct {
+	Model    string          `json:"model"`
+	Messages []ollamaMessage `json:"messages"`
+	Stream   bool            `json:"stream"`
+}
+
+// ollamaResponse covers both /api/generate (Response) and /api/chat (Message) replies.
+type ollamaResponse struct {
+	Response string `json:"response"`
+	Message  struct {
+		Content string `json:"content"`
+	} `json:"message"`
+	Done       bool   `json:"done"`
+	DoneReason string `json:"done_reason"`
+	Error      string `json:"error"`
+}
+
+// Generate sends parts to Ollama and waits for the full reply.
+func (o *Ollama) Generate(ctx context.Context, parts []Part) (*Response, error) {
+	return o.GenerateStream(ctx, parts, nil)
+}
+
+// GenerateStream reads Ollama's newline-delimited JSON stream, calling fn with each fragment. A
+// stream that ends without its final "done" message fails with io.ErrUnexpectedEOF.
+func (o *Ollama) GenerateStream(ctx context.Context, parts []Part, fn func(text string) error) (*Response, error) {
+	body, err := o.post(ctx, parts)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	var full strings.Builder
+	var doneReason string
+	done := false
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		var chunk ollamaResponse
+		if err := json.Unmarshal(line, &chunk); err != nil {
+			return nil, fmt.Errorf("error decoding ollama response: %w", err)
+		}
+		if chunk.Error != "" {
+			return nil, fmt.Errorf("ollama: %s", chunk.Error)
+		}
+		text := chunk.Response + chunk.Message.Content
+		if text != "" {
+			full.WriteString(text)
+			if fn != nil {
+				if err := fn(text); err != nil {
+					return nil, err
+				}
+			}
+		}
+		if chunk.Done {
+			doneReason = chunk.DoneReason
+			done = true
+			break
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading ollama stream: %w", err)
+	}
+	if !done {
+		// The connection was dropped: the text is truncated, so fail and let the caller retry.
+		return nil, fmt.Errorf("ollama stream ended before the final message: %w", io.ErrUnexpectedEOF)
+	}
+	return &Response{Text: full.String(), FinishReason: doneReason}, nil
+}
+
+// UploadMedia is not supported; send frames inline with Image instead.
+func (o *Ollama) UploadMedia(ctx context.Context, path string) (*Media, error) {
+	return nil, ErrNotSupported
+}
+
+// DeleteMedia is not supported.
+func (o *Ollama) DeleteMedia(ctx context.Context, m *Media) error {
+	return ErrNotSupported
+}
+
+// CountTokens is not supported; Ollama has no token counting endpoint.
+func (o *Ollama) CountTokens(ctx context.Context, parts []Part) (int, error) {
+	return 0, ErrNotSupported
+}
+
+// Ping lists the models pulled on the server and checks that the configured one is among them.
+func (o *Ollama) Ping(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, o.BaseURL+"/api/tags", nil)
+	if err != nil {
+		return fmt.Errorf("error creating ollama request: %w", err)
+	}
+	client := o.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("error calling ollama /api/tags: %w", err)
+	}
+	if resp.StatusCode/100 != 2 {
+		return httpError("ollama /api/tags", resp)
+	}
+	defer resp.Body.Close()
+
+	var tags struct {
+		Models []struct {
+			Name string `json:"name"`
+		} `json:"models"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&tags); err != nil {
+		return fmt.Errorf("error decoding ollama /api/tags response: %w", err)
+	}
+	for _, m := range tags.Models {
+		if m.Name == o.ModelName || m.Name == o.ModelName+":latest" {
+			return nil
+		}
+	}
+	return fmt.Errorf("model %q is not pulled on the ollama server; run: ollama pull %s", o.ModelName, o.ModelName)
+}
+
+// Close is a no-op.
+func (o *Ollama) Close() error {
+	return nil
+}
+
+// post sends a streaming request to /api/generate or /api/chat and returns the body on a 2xx status.
+func (o *Ollama) post(ctx context.Context, parts []Part) (io.ReadCloser, error) {
+	var prompt strings.Builder
+	var images []string
+	for _, p := range parts {
+		switch {
+		case p.Media != nil:
+			return nil, fmt.Errorf("uploaded media is not supported by ollama: %w", ErrNotSupported)
+		case p.Data != nil:
+			images = append(images, base64.StdEncoding.EncodeToString(p.Data))
+		default:
+			prompt.WriteString(p.Text)
+		}
+	}
+
+	endpoint := "/api/generate"
+	var payload any = ollamaGenerateRequest{Model: o.ModelName, Prompt: prompt.String(), Stream: true}
+	if len(images) > 0 {
+		endpoint = "/api/chat"
+		payload = ollamaChatRequest{
+			Model:    o.ModelName,
+			Messages: []ollamaMessage{{Role: "user", Content: prompt.String(), Images: images}},
+			Stream:   true,
+		}
+	}
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("error encoding ollama request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, o.BaseURL+endpoint, bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("error creating ollama request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	client := o.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error calling ollama %s: %w", endpoint, err)
+	}
+	if resp.StatusCode/100 != 2 {
+		return nil, httpError("ollama "+endpoint, resp)
+	}
+	return resp.Body, nil
+}
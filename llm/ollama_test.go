@@ -0,0 +1,197 @@
+package llm
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/utkarsh-cpu/videoSummaryGo/failure"
+	"github.com/utkarsh-cpu/videoSummaryGo/retry"
+)
+
+func TestNewOllamaBaseURL(t *testing.T) {
+	tests := map[string]string{
+		"":                           DefaultOllamaBaseURL,
+		"http://gpu-box:11434/":      "http://gpu-box:11434",
+		"https://ollama.example.com": "https://ollama.example.com",
+		"127.0.0.1:11434":            "http://127.0.0.1:11434",
+		"0.0.0.0:8080":               "http://0.0.0.0:8080",
+		"gpu-box":                    "http://gpu-box:11434",
+		"[::1]:11434":                "http://[::1]:11434",
+		"[::1]":                      "http://[::1]:11434",
+	}
+	for in, want := range tests {
+		if got := NewOllama(in, "llama3").BaseURL; got != want {
+			t.Errorf("NewOllama(%q).BaseURL = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestOllamaGenerateStream(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/generate" {
+			t.Errorf("request to %s, want /api/generate", r.URL.Path)
+		}
+		var req ollamaGenerateRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("decoding request: %v", err)
+		}
+		if req.Model != "llama3" || req.Prompt != "Summarize this." || !req.Stream {
+			t.Errorf("request = %+v", req)
+		}
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		fmt.Fprintln(w, `{"response":"Hello","done":false}`)
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, `{"response":", world","done":false}`)
+		fmt.Fprintln(w, `{"response":"","done":true,"done_reason":"stop"}`)
+		fmt.Fprintln(w, `{"response":"ignored after done","done":false}`)
+	}))
+	defer srv.Close()
+
+	var fragments []string
+	resp, err := NewOllama(srv.URL, "llama3").GenerateStream(context.Background(), []Part{Text("Summarize this.")}, func(text string) error {
+		fragments = append(fragments, text)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Text != "Hello, world" || resp.FinishReason != "stop" {
+		t.Errorf("response = %+v", resp)
+	}
+	if want := []string{"Hello", ", world"}; !slices.Equal(fragments, want) {
+		t.Errorf("fragments = %q, want %q", fragments, want)
+	}
+}
+
+func TestOllamaTruncatedStream(t *testing.T) {
+	requests := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		fmt.Fprintln(w, `{"response":"A trunc","done":false}`)
+		if requests > 1 {
+			fmt.Fprintln(w, `{"response":"ated summary.","done":true,"done_reason":"stop"}`)
+		}
+	}))
+	defer srv.Close()
+
+	_, err := NewOllama(srv.URL, "llama3").Generate(context.Background(), []Part{Text("hi")})
+	if !errors.Is(err, io.ErrUnexpectedEOF) || !retry.Retryable(err) {
+		t.Fatalf("error = %v, want a retryable io.ErrUnexpectedEOF", err)
+	}
+
+	// Generate passes no fragments on, so the whole call is retried.
+	requests = 0
+	model := WithRetry(NewOllama(srv.URL, "llama3"), retry.Policy{InitialDelay: time.Millisecond, Jitter: -1})
+	resp, err := model.Generate(context.Background(), []Part{Text("hi")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Text != "A truncated summary." || requests != 2 {
+		t.Errorf("response %q after %d requests, want the full text after 2", resp.Text, requests)
+	}
+}
+
+func TestOllamaChatImages(t *testing.T) {
+	frame := []byte{0xff, 0xd8, 0xff, 0xe0}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/chat" {
+			t.Errorf("request to %s, want /api/chat", r.URL.Path)
+		}
+		var req ollamaChatRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("decoding request: %v", err)
+		}
+		if len(req.Messages) != 1 {
+			t.Fatalf("got %d messages, want 1", len(req.Messages))
+		}
+		m := req.Messages[0]
+		want := base64.StdEncoding.EncodeToString(frame)
+		if m.Role != "user" || m.Content != "Read the slide." || !slices.Equal(m.Images, []string{want, want}) {
+			t.Errorf("message = %+v", m)
+		}
+		fmt.Fprintln(w, `{"message":{"role":"assistant","content":"Slide 1"},"done":false}`)
+		fmt.Fprintln(w, `{"message":{"role":"assistant","content":""},"done":true,"done_reason":"stop"}`)
+	}))
+	defer srv.Close()
+
+	resp, err := NewOllama(srv.URL, "llava").Generate(context.Background(), []Part{Text("Read the slide."), Image("image/jpeg", frame), Image("image/jpeg", frame)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Text != "Slide 1" {
+		t.Errorf("text = %q, want %q", resp.Text, "Slide 1")
+	}
+}
+
+func TestOllamaErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		body   string
+		kind   failure.Kind
+	}{
+		{"server error", http.StatusInternalServerError, `{"error":"model crashed"}`, failure.LLM},
+		{"busy", http.StatusTooManyRequests, `{"error":"server busy"}`, failure.LLMQuota},
+		{"not found", http.StatusNotFound, `{"error":"model 'llama9' not found"}`, failure.LLM},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Retry-After", "7")
+				w.WriteHeader(tt.status)
+				fmt.Fprint(w, tt.body)
+			}))
+			defer srv.Close()
+
+			_, err := NewOllama(srv.URL, "llama3").Generate(context.Background(), []Part{Text("hi")})
+			var apiErr *APIError
+			if !errors.As(err, &apiErr) {
+				t.Fatalf("error = %v, want an *APIError", err)
+			}
+			if apiErr.StatusCode != tt.status || apiErr.RetryAfter.Seconds() != 7 {
+				t.Errorf("APIError = %+v", apiErr)
+			}
+			if kind := failure.KindOf(err); kind != tt.kind {
+				t.Errorf("kind = %s, want %s", kind, tt.kind)
+			}
+		})
+	}
+
+	t.Run("error in stream", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprintln(w, `{"response":"partial","done":false}`)
+			fmt.Fprintln(w, `{"error":"out of memory"}`)
+		}))
+		defer srv.Close()
+
+		if _, err := NewOllama(srv.URL, "llama3").Generate(context.Background(), []Part{Text("hi")}); err == nil {
+			t.Error("Generate succeeded, want the stream's error")
+		}
+	})
+}
+
+func TestOllamaPing(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/tags" {
+			t.Errorf("request to %s, want /api/tags", r.URL.Path)
+		}
+		fmt.Fprint(w, `{"models":[{"name":"llama3:latest"},{"name":"llava:13b"}]}`)
+	}))
+	defer srv.Close()
+
+	for model, ok := range map[string]bool{"llama3": true, "llava:13b": true, "llava": false, "mistral": false} {
+		err := NewOllama(srv.URL, model).Ping(context.Background())
+		if (err == nil) != ok {
+			t.Errorf("Ping with model %s: %v", model, err)
+		}
+	}
+}
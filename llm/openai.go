@@ -0,0 +1,296 @@
+package llm
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/utkarsh-cpu/videoSummaryGo/failure"
+)
+
+// DefaultOpenAIBaseURL is used when OpenAI.BaseURL is empty.
+const DefaultOpenAIBaseURL = "https://api.openai.com/v1"
+
+// OpenAI is the LLM implementation for OpenAI-compatible chat completions servers
+// such as the OpenAI API, llama.cpp server, vLLM or LM Studio.
+type OpenAI struct {
+	BaseURL    string // e.g. http://localhost:8080/v1
+	ModelName  string
+	APIKey     string // optional for local servers
+	HTTPClient *http.Client
+}
+
+// NewOpenAI returns an OpenAI-compatible client for model at baseURL.
+func NewOpenAI(baseURL string, model string, apiKey string) *OpenAI {
+	if baseURL == "" {
+		baseURL = DefaultOpenAIBaseURL
+	}
+	return &OpenAI{BaseURL: strings.TrimSuffix(baseURL, "/"), ModelName: model, APIKey: apiKey, HTTPClient: http.DefaultClient}
+}
+
+// Name returns "openai".
+func (o *OpenAI) Name() string {
+	return "openai"
+}
+
+// Model returns the model name sent with each request.
+func (o *OpenAI) Model() string {
+	return o.ModelName
+}
+
+type openAIContent struct {
+	Type     string          `json:"type"`
+	Text     string          `json:"text,omitempty"`
+	ImageURL *openAIImageURL `json:"image_url,omitempty"`
+}
+
+type openAIImageURL struct {
+	URL string `json:"url"`
+}
+
+type openAIMessage struct {
+	Role    string `json:"role"`
+	Content any    `json:"content"` // string, or []openAIContent for multimodal prompts
+}
+
+type openAIRequest struct {
+	Model    string          `json:"model"`
+	Messages []openAIMessage `json:"messages"`
+	Stream   bool            `json:"stream,omitempty"`
+}
+
+type openAIResponse struct {
+	Choices []struct {
+		Message struct {
+			Content string `json:"content"`
+		} `json:"message"`
+		Delta struct {
+			Content string `json:"content"`
+		} `json:"delta"`
+		FinishReason string `json:"finish_reason"`
+	} `json:"choices"`
+}
+
+// Generate sends parts as a single user message to /chat/completions.
+func (o *OpenAI) Generate(ctx context.Context, parts []Part) (*Response, error) {
+	body, err := o.post(ctx, parts, false)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	var resp openAIResponse
+	if err := json.NewDecoder(body).Decode(&resp); err != nil {
+		return nil, fmt.Errorf("error decoding chat completion: %w", err)
+	}
+	if len(resp.Choices) == 0 {
+		return nil, failure.Wrap(failure.LLM, fmt.Errorf("chat completion returned no choices"))
+	}
+	return openAIResult(&Response{Text: resp.Choices[0].Message.Content, FinishReason: resp.Choices[0].FinishReason})
+}
+
+// GenerateStream requests a streamed completion and reads the server-sent events.
+func (o *OpenAI) GenerateStream(ctx context.Context, parts []Part, fn func(text string) error) (*Response, error) {
+	body, err := o.post(ctx, parts, true)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	var full strings.Builder
+	var finishReason string
+	err = readEvents(body, func(data string) error {
+		var chunk openAIResponse
+		if err := json.Unmarshal([]byte(data), &chunk); err != nil {
+			return fmt.Errorf("error decoding chat completion chunk: %w", err)
+		}
+		for _, c := range chunk.Choices {
+			if c.FinishReason != "" {
+				finishReason = c.FinishReason
+			}
+			if c.Delta.Content == "" {
+				continue
+			}
+			full.WriteString(c.Delta.Content)
+			if fn != nil {
+				if err := fn(c.Delta.Content); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return openAIResult(&Response{Text: full.String(), FinishReason: finishReason})
+}
+
+// readEvents calls fn with the data of each server-sent event in r until the "[DONE]" event or
+// the end of the stream. The data lines of an event are joined with newlines; other fields and
+// comments are ignored.
+func readEvents(r io.Reader, fn func(data string) error) error {
+	var data []string
+	// dispatch passes the buffered event to fn and reports whether it was the last one.
+	dispatch := func() (bool, error) {
+		if len(data) == 0 {
+			return false, nil
+		}
+		event := strings.Join(data, "\n")
+		data = data[:0]
+		if strings.TrimSpace(event) == "[DONE]" {
+			return true, nil
+		}
+		return false, fn(event)
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			if done, err := dispatch(); done || err != nil {
+				return err
+			}
+			continue
+		}
+		if value, ok := strings.CutPrefix(line, "data:"); ok {
+			data = append(data, strings.TrimPrefix(value, " "))
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("error reading chat completion stream: %w", err)
+	}
+	// A stream may end without the blank line after its last event.
+	_, err := dispatch()
+	return err
+}
+
+// openAIResult turns an empty response stopped by the server's content filter into an error.
+func openAIResult(resp *Response) (*Response, error) {
+	if resp.FinishReason == "content_filter" && resp.Text == "" {
+		return nil, failure.Wrap(failure.LLMBlocked, errors.New("chat completion blocked by content filter"))
+	}
+	return resp, nil
+}
+
+// UploadMedia is not supported; send frames inline with Image instead.
+func (o *OpenAI) UploadMedia(ctx context.Context, path string) (*Media, error) {
+	return nil, ErrNotSupported
+}
+
+// DeleteMedia is not supported.
+func (o *OpenAI) DeleteMedia(ctx context.Context, m *Media) error {
+	return ErrNotSupported
+}
+
+// CountTokens is not supported; the chat completions API has no token counting endpoint.
+func (o *OpenAI) CountTokens(ctx context.Context, parts []Part) (int, error) {
+	return 0, ErrNotSupported
+}
+
+// Ping lists the server's models, which needs a valid API key for the OpenAI API.
+func (o *OpenAI) Ping(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, o.BaseURL+"/models", nil)
+	if err != nil {
+		return fmt.Errorf("error creating models request: %w", err)
+	}
+	if o.APIKey != "" {
+		req.Header.Set("Authorization", "Bearer "+o.APIKey)
+	}
+	client := o.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("error calling models: %w", err)
+	}
+	if resp.StatusCode/100 != 2 {
+		return httpError("models", resp)
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// Close is a no-op.
+func (o *OpenAI) Close() error {
+	return nil
+}
+
+// post sends a chat completions request and returns the response body on a 2xx status.
+func (o *OpenAI) post(ctx context.Context, parts []Part, stream bool) (io.ReadCloser, error) {
+	content, err := openAIParts(parts)
+	if err != nil {
+		return nil, err
+	}
+	payload, err := json.Marshal(openAIRequest{
+		Model:    o.ModelName,
+		Messages: []openAIMessage{{Role: "user", Content: content}},
+		Stream:   stream,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error encoding chat completion request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, o.BaseURL+"/chat/completions", bytes.NewReader(payload))
+	if err != nil {
+		return nil, fmt.Errorf("error creating chat completion request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if o.APIKey != "" {
+		req.Header.Set("Authorization", "Bearer "+o.APIKey)
+	}
+
+	client := o.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error calling chat completions: %w", err)
+	}
+	if resp.StatusCode/100 != 2 {
+		return nil, httpError("chat completions", resp)
+	}
+	return resp.Body, nil
+}
+
+// openAIParts converts parts to a plain string for text-only prompts, or to
+// content parts with base64 data URLs when images are present.
+func openAIParts(parts []Part) (any, error) {
+	multimodal := false
+	for _, p := range parts {
+		if p.Media != nil {
+			return nil, fmt.Errorf("uploaded media is not supported by OpenAI-compatible backends: %w", ErrNotSupported)
+		}
+		if p.Data != nil {
+			multimodal = true
+		}
+	}
+	if !multimodal {
+		var text strings.Builder
+		for _, p := range parts {
+			text.WriteString(p.Text)
+		}
+		return text.String(), nil
+	}
+
+	var content []openAIContent
+	for _, p := range parts {
+		if p.Data != nil {
+			url := "data:" + p.MIMEType + ";base64," + base64.StdEncoding.EncodeToString(p.Data)
+			content = append(content, openAIContent{Type: "image_url", ImageURL: &openAIImageURL{URL: url}})
+		} else {
+			content = append(content, openAIContent{Type: "text", Text: p.Text})
+		}
+	}
+	return content, nil
+}
@@ -0,0 +1,211 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/utkarsh-cpu/videoSummaryGo/failure"
+)
+
+// openAIServer returns a stand-in chat completions server that checks each request's
+// Authorization header and decodes it into *got before calling reply.
+func openAIServer(t *testing.T, apiKey string, got *map[string]any, reply http.HandlerFunc) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/v1/chat/completions" {
+			t.Errorf("request %s %s, want POST /v1/chat/completions", r.Method, r.URL.Path)
+		}
+		want := ""
+		if apiKey != "" {
+			want = "Bearer " + apiKey
+		}
+		if auth := r.Header.Get("Authorization"); auth != want {
+			t.Errorf("Authorization = %q, want %q", auth, want)
+		}
+		if got != nil {
+			if err := json.NewDecoder(r.Body).Decode(got); err != nil {
+				t.Errorf("decoding request: %v", err)
+			}
+		}
+		reply(w, r)
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestOpenAIGenerate(t *testing.T) {
+	var req map[string]any
+	srv := openAIServer(t, "sk-test-123456", &req, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"choices":[{"message":{"role":"assistant","content":"A summary."},"finish_reason":"stop"}]}`)
+	})
+
+	resp, err := NewOpenAI(srv.URL+"/v1/", "gpt-4o", "sk-test-123456").Generate(context.Background(), []Part{Text("Summarize "), Text("this.")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Text != "A summary." || resp.FinishReason != "stop" {
+		t.Errorf("response = %+v", resp)
+	}
+	if req["model"] != "gpt-4o" || req["stream"] != nil {
+		t.Errorf("request = %v", req)
+	}
+	messages, _ := req["messages"].([]any)
+	if len(messages) != 1 {
+		t.Fatalf("got %d messages, want 1", len(messages))
+	}
+	// Text-only prompts are sent as a plain string for servers without multimodal support.
+	if m := messages[0].(map[string]any); m["role"] != "user" || m["content"] != "Summarize this." {
+		t.Errorf("message = %v", m)
+	}
+}
+
+func TestOpenAIImageParts(t *testing.T) {
+	var req struct {
+		Messages []struct {
+			Content []openAIContent `json:"content"`
+		} `json:"messages"`
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("decoding request: %v", err)
+		}
+		fmt.Fprint(w, `{"choices":[{"message":{"content":"Slide text"},"finish_reason":"stop"}]}`)
+	}))
+	defer srv.Close()
+
+	parts := []Part{Text("Read the frames."), Image("image/jpeg", []byte("jpeg")), Image("image/png", []byte("png"))}
+	if _, err := NewOpenAI(srv.URL, "llava", "").Generate(context.Background(), parts); err != nil {
+		t.Fatal(err)
+	}
+	want := []openAIContent{
+		{Type: "text", Text: "Read the frames."},
+		{Type: "image_url", ImageURL: &openAIImageURL{URL: "data:image/jpeg;base64,anBlZw=="}},
+		{Type: "image_url", ImageURL: &openAIImageURL{URL: "data:image/png;base64,cG5n"}},
+	}
+	if len(req.Messages) != 1 || !slices.EqualFunc(req.Messages[0].Content, want, func(a, b openAIContent) bool {
+		return a.Type == b.Type && a.Text == b.Text && (a.ImageURL == nil) == (b.ImageURL == nil) && (a.ImageURL == nil || a.ImageURL.URL == b.ImageURL.URL)
+	}) {
+		t.Errorf("request messages = %+v", req.Messages)
+	}
+
+	if _, err := NewOpenAI(srv.URL, "llava", "").Generate(context.Background(), []Part{MediaPart(&Media{URI: "files/1"})}); !errors.Is(err, ErrNotSupported) {
+		t.Errorf("uploaded media: error = %v, want ErrNotSupported", err)
+	}
+}
+
+func TestOpenAIGenerateStream(t *testing.T) {
+	var req map[string]any
+	srv := openAIServer(t, "sk-test-123456", &req, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, ": keep-alive comment\n\n")
+		fmt.Fprint(w, "data: {\"choices\":[{\"delta\":{\"role\":\"assistant\"}}]}\n\n")
+		fmt.Fprint(w, "data: {\"choices\":[{\"delta\":{\"content\":\"Hello\"}}]}\n\n")
+		// One event whose JSON is split over several data lines.
+		fmt.Fprint(w, "event: message\ndata: {\"choices\":[{\"delta\":\ndata: {\"content\":\", world\"}}]}\n\n")
+		fmt.Fprint(w, "data:{\"choices\":[{\"delta\":{},\"finish_reason\":\"stop\"}]}\n\n")
+		fmt.Fprint(w, "data: [DONE]\n\n")
+		fmt.Fprint(w, "data: not json after the end\n\n")
+	})
+
+	var fragments []string
+	resp, err := NewOpenAI(srv.URL+"/v1", "gpt-4o", "sk-test-123456").GenerateStream(context.Background(), []Part{Text("hi")}, func(text string) error {
+		fragments = append(fragments, text)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Text != "Hello, world" || resp.FinishReason != "stop" {
+		t.Errorf("response = %+v", resp)
+	}
+	if want := []string{"Hello", ", world"}; !slices.Equal(fragments, want) {
+		t.Errorf("fragments = %q, want %q", fragments, want)
+	}
+	if req["stream"] != true {
+		t.Errorf("request stream = %v, want true", req["stream"])
+	}
+}
+
+func TestOpenAIStreamWithoutDone(t *testing.T) {
+	srv := openAIServer(t, "", nil, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "data: {\"choices\":[{\"delta\":{\"content\":\"last\"},\"finish_reason\":\"length\"}]}")
+	})
+
+	resp, err := NewOpenAI(srv.URL+"/v1", "local", "").GenerateStream(context.Background(), []Part{Text("hi")}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Text != "last" || resp.FinishReason != "length" {
+		t.Errorf("response = %+v", resp)
+	}
+}
+
+func TestOpenAIErrors(t *testing.T) {
+	tests := []struct {
+		name       string
+		status     int
+		retryAfter string
+		kind       failure.Kind
+		wantDelay  time.Duration
+	}{
+		{"rate limited", http.StatusTooManyRequests, "20", failure.LLMQuota, 20 * time.Second},
+		{"bad key", http.StatusUnauthorized, "", failure.LLMAuth, 0},
+		{"overloaded", http.StatusServiceUnavailable, "3", failure.LLM, 3 * time.Second},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := openAIServer(t, "sk-test-123456", nil, func(w http.ResponseWriter, r *http.Request) {
+				if tt.retryAfter != "" {
+					w.Header().Set("Retry-After", tt.retryAfter)
+				}
+				w.WriteHeader(tt.status)
+				fmt.Fprint(w, `{"error":{"message":"no"}}`)
+			})
+
+			for _, stream := range []bool{false, true} {
+				client := NewOpenAI(srv.URL+"/v1", "gpt-4o", "sk-test-123456")
+				var err error
+				if stream {
+					_, err = client.GenerateStream(context.Background(), []Part{Text("hi")}, nil)
+				} else {
+					_, err = client.Generate(context.Background(), []Part{Text("hi")})
+				}
+				var apiErr *APIError
+				if !errors.As(err, &apiErr) {
+					t.Fatalf("stream=%v: error = %v, want an *APIError", stream, err)
+				}
+				if apiErr.HTTPStatus() != tt.status || apiErr.RetryDelay() != tt.wantDelay {
+					t.Errorf("stream=%v: APIError status %d, retry after %s", stream, apiErr.HTTPStatus(), apiErr.RetryDelay())
+				}
+				if kind := failure.KindOf(err); kind != tt.kind {
+					t.Errorf("stream=%v: kind = %s, want %s", stream, kind, tt.kind)
+				}
+			}
+		})
+	}
+}
+
+func TestOpenAIContentFilter(t *testing.T) {
+	srv := openAIServer(t, "", nil, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"choices":[{"message":{"content":""},"finish_reason":"content_filter"}]}`)
+	})
+	_, err := NewOpenAI(srv.URL+"/v1", "gpt-4o", "").Generate(context.Background(), []Part{Text("hi")})
+	if kind := failure.KindOf(err); kind != failure.LLMBlocked {
+		t.Errorf("Generate: error %v with kind %s, want %s", err, kind, failure.LLMBlocked)
+	}
+
+	streamSrv := openAIServer(t, "", nil, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "data: {\"choices\":[{\"delta\":{},\"finish_reason\":\"content_filter\"}]}\n\ndata: [DONE]\n\n")
+	})
+	_, err = NewOpenAI(streamSrv.URL+"/v1", "gpt-4o", "").GenerateStream(context.Background(), []Part{Text("hi")}, nil)
+	if kind := failure.KindOf(err); kind != failure.LLMBlocked {
+		t.Errorf("GenerateStream: error %v with kind %s, want %s", err, kind, failure.LLMBlocked)
+	}
+}
@@ -0,0 +1,34 @@
+package llm
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/utkarsh-cpu/videoSummaryGo/failure"
+	"github.com/utkarsh-cpu/videoSummaryGo/progress"
+	"github.com/utkarsh-cpu/videoSummaryGo/secret"
+)
+
+// SendPrompt sends prompt to model and returns the text of the response. The response is also
+// written to w when it is non-nil; a failed write is returned with the text. Retries are up to model: clients created by New retry
+// transient errors according to Options.Retry (see Retrying). The returned error is classified
+// with a failure.Kind.
+func SendPrompt(ctx context.Context, model LLM, prompt []Part, w io.Writer, videoIndex int) (string, error) {
+	progress.Printf(ctx, "Sending combined prompt for video %d to LLM...", videoIndex)
+	startTime := time.Now()
+	resp, err := model.Generate(ctx, prompt)
+	if err != nil {
+		return "", failure.Wrap(failure.LLM, secret.Error(err))
+	}
+	duration := time.Since(startTime)
+	progress.Printf(ctx, "LLM response received for video %d in %v.", videoIndex, duration)
+	if w != nil {
+		if _, err := fmt.Fprintln(w, resp.Text); err != nil {
+			return resp.Text, fmt.Errorf("error writing LLM response: %w", err)
+		}
+	}
+	progress.Printf(ctx, "Combined prompt processed for video %d.", videoIndex)
+	return resp.Text, nil
+}
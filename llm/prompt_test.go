@@ -0,0 +1,44 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"strings"
+	"testing"
+
+	"github.com/utkarsh-cpu/videoSummaryGo/failure"
+)
+
+type stubLLM struct {
+	LLM
+	resp *Response
+	err  error
+}
+
+func (m *stubLLM) Generate(ctx context.Context, parts []Part) (*Response, error) {
+	return m.resp, m.err
+}
+
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) { return 0, fs.ErrClosed }
+
+func TestSendPrompt(t *testing.T) {
+	ctx := context.Background()
+	var b strings.Builder
+	if text, err := SendPrompt(ctx, &stubLLM{resp: &Response{Text: "summary"}}, nil, &b, 0); err != nil || text != "summary" || b.String() != "summary\n" {
+		t.Errorf("SendPrompt = %q, %v; wrote %q", text, err, b.String())
+	}
+
+	// A failed write is returned along with the text.
+	text, err := SendPrompt(ctx, &stubLLM{resp: &Response{Text: "summary"}}, nil, failingWriter{}, 0)
+	if text != "summary" || !errors.Is(err, fs.ErrClosed) {
+		t.Errorf("SendPrompt with a failing writer = %q, %v", text, err)
+	}
+
+	_, err = SendPrompt(ctx, &stubLLM{err: errors.New("boom")}, nil, nil, 0)
+	if failure.KindOf(err) != failure.LLM {
+		t.Errorf("generate error kind = %v, want %v", failure.KindOf(err), failure.LLM)
+	}
+}
@@ -0,0 +1,82 @@
+package llm
+
+import (
+	"context"
+
+	"github.com/utkarsh-cpu/videoSummaryGo/progress"
+	"github.com/utkarsh-cpu/videoSummaryGo/retry"
+)
+
+// Retrying wraps an LLM so that generation, uploads and token counting are retried according
+// to Policy. Transient errors (rate limits, 5xx, timeouts) are retried with backoff; auth
+// failures and blocked content fail on the first attempt.
+type Retrying struct {
+	LLM
+	Policy retry.Policy
+	// Logger, when set, receives the progress of calls through r, such as retries and upload
+	// waits, in place of the Logger of their context.
+	Logger progress.Logger
+}
+
+// WithRetry returns model wrapped in a Retrying with policy.
+func WithRetry(model LLM, policy retry.Policy) *Retrying {
+	return &Retrying{LLM: model, Policy: policy}
+}
+
+// Generate retries the wrapped Generate.
+func (r *Retrying) Generate(ctx context.Context, parts []Part) (*Response, error) {
+	var resp *Response
+	ctx = progress.NewContext(ctx, r.Logger)
+	err := r.Policy.Do(ctx, r.LLM.Name()+" generate", func() error {
+		var err error
+		resp, err = r.LLM.Generate(ctx, parts)
+		return err
+	})
+	return resp, err
+}
+
+// GenerateStream retries the wrapped GenerateStream as long as no text has been passed to fn yet.
+func (r *Retrying) GenerateStream(ctx context.Context, parts []Part, fn func(text string) error) (*Response, error) {
+	var resp *Response
+	streamed := false
+	ctx = progress.NewContext(ctx, r.Logger)
+	err := r.Policy.Do(ctx, r.LLM.Name()+" stream", func() error {
+		var err error
+		resp, err = r.LLM.GenerateStream(ctx, parts, func(text string) error {
+			streamed = true
+			if fn == nil {
+				return nil
+			}
+			return fn(text)
+		})
+		if err != nil && streamed {
+			return retry.Permanent(err)
+		}
+		return err
+	})
+	return resp, err
+}
+
+// UploadMedia retries the wrapped UploadMedia.
+func (r *Retrying) UploadMedia(ctx context.Context, path string) (*Media, error) {
+	var media *Media
+	ctx = progress.NewContext(ctx, r.Logger)
+	err := r.Policy.Do(ctx, r.LLM.Name()+" upload of "+path, func() error {
+		var err error
+		media, err = r.LLM.UploadMedia(ctx, path)
+		return err
+	})
+	return media, err
+}
+
+// CountTokens retries the wrapped CountTokens.
+func (r *Retrying) CountTokens(ctx context.Context, parts []Part) (int, error) {
+	var n int
+	ctx = progress.NewContext(ctx, r.Logger)
+	err := r.Policy.Do(ctx, r.LLM.Name()+" token count", func() error {
+		var err error
+		n, err = r.LLM.CountTokens(ctx, parts)
+		return err
+	})
+	return n, err
+}
@@ -1,627 +1,294 @@
 package main
 
 import (
-	"bytes"
 	"context"
+	"errors"
+	"flag"
 	"fmt"
-	"image"
-	"image/jpeg"
-	"io/fs"
 	"log"
 	"os"
-	"os/exec"
-	"path/filepath"
+	"os/signal"
 	"runtime"
-	"strconv"
 	"strings"
-	"sync"
-	"time"
-
-	"github.com/google/generative-ai-go/genai"
-	"google.golang.org/api/option"
+	"syscall"
+	"text/template"
+
+	"github.com/utkarsh-cpu/videoSummaryGo/output"
+	"github.com/utkarsh-cpu/videoSummaryGo/pipeline"
+	"github.com/utkarsh-cpu/videoSummaryGo/preflight"
+	"github.com/utkarsh-cpu/videoSummaryGo/progress"
+	"github.com/utkarsh-cpu/videoSummaryGo/secret"
+	"github.com/utkarsh-cpu/videoSummaryGo/subtitle"
 )
 
-// ChunkData struct
-type ChunkData struct {
-	VideoPath  string
-	AudioPath  string
-	ChunkNum   int
-	Err        error
-	VideoIndex int
-	BaseName   string
-}
-
-// setLlmApi function
-func setLlmApi(llm string, apiKey string) (*genai.Client, *genai.GenerativeModel, context.Context) {
-	ctx := context.Background()
-	client, err := genai.NewClient(ctx, option.WithAPIKey(apiKey))
-	if err != nil {
-		log.Fatal(err)
-	}
-	model := client.GenerativeModel(llm)
-	fmt.Println("LLM API setup complete.")
-	return client, model, ctx
-}
+// errPartialFailure is returned by VideoSummary when every video was processed but some stages failed.
+var errPartialFailure = errors.New("some videos had failures")
 
+// Exit codes.
 const (
-	maxRetries = 3                // Maximum number of retry attempts for LLM calls
-	retryDelay = 15 * time.Second // Delay between retry attempts
+	exitFailure     = 1   // the run could not start or was aborted
+	exitPartial     = 2   // outputs were written but some chunks or summaries failed
+	exitInterrupted = 130 // stopped by SIGINT/SIGTERM
 )
 
-// sentLlmPrompt function
-func sentLlmPrompt(model *genai.GenerativeModel, prompt []genai.Part, ctx context.Context, file *os.File, videoIndex int) string {
-	for attempt := 0; attempt <= maxRetries; attempt++ {
-		fmt.Printf("Sending combined prompt for video %d to LLM, attempt %d...\n", videoIndex, attempt+1)
-		startTime := time.Now()
-		resp, err := model.GenerateContent(ctx, prompt...)
-		if err == nil {
-			duration := time.Since(startTime)
-			fmt.Printf("LLM response received for video %d in %v.\n", videoIndex, duration)
-			var llmResponse string
-			for _, c := range resp.Candidates {
-				if c.Content != nil {
-					for _, part := range c.Content.Parts {
-						if text, ok := part.(genai.Text); ok {
-							llmResponse += string(text)
-							if file != nil { // Check if file is nil before writing
-								if _, err := fmt.Fprintln(file, string(text)); err != nil {
-									log.Println("Error writing to file:", err)
-								}
-							}
-						}
-					}
-				}
-			}
-			fmt.Printf("Combined prompt processed and written to file for video %d.\n", videoIndex)
-			return llmResponse
-		}
-
-		log.Printf("Error generating content for video %d (attempt %d): %v\n", videoIndex, attempt+1, err)
-		if attempt < maxRetries {
-			fmt.Printf("Retrying in %v...\n", retryDelay)
-			time.Sleep(retryDelay)
-		} else {
-			fmt.Printf("Max retries reached for video %d. Aborting LLM call.\n", videoIndex)
-			return "" // Return empty string if max retries reached
-		}
-	}
-	return "" // Should not reach here, but added for completeness
+// progressLog prints the progress reported by the pipeline and LLM clients to stdout, with API
+// keys masked like in every other log line.
+var progressLog = log.New(secret.Writer(os.Stdout), "", 0)
+
+// runOptions are the run command's settings beyond the pipeline configuration.
+type runOptions struct {
+	formats    map[string]bool // output formats to write
+	embedMode  output.EmbedMode
+	skipChecks bool // do not run the preflight checks
+	// reportTemplate renders the Markdown and PDF reports; nil selects the built-in template.
+	reportTemplate *template.Template
+	subtitles      subtitle.Options // cue layout of the SRT and VTT files
 }
 
-// chunkVideo function
-func chunkVideo(videoPath string, chunkDuration int, videoIndex int, baseName string) ([]ChunkData, error) {
-	_, err := exec.LookPath("ffmpeg")
-	if err != nil {
-		return nil, fmt.Errorf("ffmpeg not found in PATH: %w", err)
-	}
-
-	tempDir, err := os.MkdirTemp("", "video_chunks")
-	if err != nil {
-		return nil, fmt.Errorf("error creating temporary directory: %w", err)
-	}
-
-	cmd := exec.Command("ffprobe", "-v", "error", "-show_entries", "format=duration", "-of", "default=noprint_wrappers=1:nokey=1", videoPath)
-	output, err := cmd.CombinedOutput()
-	if err != nil {
-		os.RemoveAll(tempDir)
-		return nil, fmt.Errorf("error getting video duration: %w, output: %s", err, string(output))
-	}
-	duration, err := strconv.ParseFloat(strings.TrimSpace(string(output)), 64)
-	if err != nil {
-		os.RemoveAll(tempDir)
-		return nil, fmt.Errorf("error parsing video duration: %w", err)
-	}
-
-	numChunks := int(duration / float64(chunkDuration))
-	if int(duration)%chunkDuration != 0 {
-		numChunks++
-	}
-
-	var chunks []ChunkData
-
-	for i := 0; i < numChunks; i++ {
-		startTime := i * chunkDuration
-		chunkVideoPath := fmt.Sprintf("%s/chunk_%d_video_%d.mp4", tempDir, i, videoIndex)
-		chunkAudioPath := fmt.Sprintf("%s/chunk_%d_video_%d.wav", tempDir, i, videoIndex)
-
-		cmd := exec.Command("ffmpeg",
-			"-ss", fmt.Sprintf("%d", startTime),
-			"-i", videoPath,
-			"-t", fmt.Sprintf("%d", chunkDuration),
-			"-c", "copy",
-			"-an", chunkVideoPath,
-			"-ss", fmt.Sprintf("%d", startTime),
-			"-i", videoPath,
-			"-t", fmt.Sprintf("%d", chunkDuration),
-			"-vn",
-			"-acodec", "pcm_s16le", // 16-bit WAV audio
-			chunkAudioPath,
-		)
-
-		output, err = cmd.CombinedOutput()
-		if err != nil {
-			return nil, fmt.Errorf("error creating video chunk %d for video %d: %w, output: %s", i, videoIndex, err, string(output))
+// VideoSummary checks the toolchain, then runs the pipeline over inputPath and writes each video's
+// outputs in the selected formats to the directory chosen by layout. When opts.embedMode is set,
+// a copy of each video with the generated subtitles is written as well. If ctx is cancelled, no
+// new work is started and the outputs of the interrupted video are written from the chunks that
+// finished. It returns errPartialFailure if any video had
+// failures; they are listed in each video's JSON result and report.
+func VideoSummary(ctx context.Context, cfg pipeline.Config, inputPath string, layout *output.Layout, opts runOptions) error {
+	if !opts.skipChecks {
+		fmt.Println("Checking tools, models, credentials and disk space...")
+		report := preflight.Run(ctx, cfg, preflight.Options{Input: inputPath, Embed: opts.embedMode})
+		report.Problems().Print(os.Stdout)
+		if report.Failed() {
+			return fmt.Errorf("preflight checks failed: fix the problems above (see '%s doctor') or pass --skip-checks", progName)
 		}
-		chunks = append(chunks, ChunkData{VideoPath: chunkVideoPath, AudioPath: chunkAudioPath, ChunkNum: i, VideoIndex: videoIndex, BaseName: baseName})
-	}
-
-	return chunks, nil
-}
-
-// transcribeAudioWhisperCLI function
-func transcribeAudioWhisperCLI(audioPath string, whisperCLIPath string, whisperModelPath string, videoIndex int, chunkNum int, threads int, language string) (string, error) {
-	cmdArgs := []string{
-		"--model", whisperModelPath,
-		"--threads", fmt.Sprintf("%d", threads),
-	}
-	if language != "" {
-		cmdArgs = append(cmdArgs, "--language", language)
-	}
-	cmdArgs = append(cmdArgs, audioPath)
-
-	cmd := exec.Command(whisperCLIPath, cmdArgs...)
-	var out bytes.Buffer
-	var stderr bytes.Buffer
-	cmd.Stdout = &out
-	cmd.Stderr = &stderr
-
-	fmt.Printf("Starting whisper-cli for video %d chunk %d, Audio Path: %s\n", videoIndex, chunkNum, audioPath)
-	startTime := time.Now()
-
-	err := cmd.Run()
-	duration := time.Since(startTime)
-	fmt.Printf("Whisper-cli finished for video %d chunk %d in %v\n", videoIndex, chunkNum, duration)
-
-	if err != nil {
-		return "", fmt.Errorf("error running whisper-cli for video %d chunk %d: %w, stderr: %s", videoIndex, chunkNum, err, stderr.String())
-	}
-
-	transcript := out.String()
-	return transcript, nil
-}
-
-// extractFrames function
-func extractFrames(videoPath string, videoIndex int, chunkNum int) ([]string, error) {
-	tempDir, err := os.MkdirTemp("", fmt.Sprintf("frames_video%d_chunk%d", videoIndex, chunkNum))
-	if err != nil {
-		return nil, fmt.Errorf("error creating temporary directory for frames: %w", err)
-	}
-
-	// Extract frames at 1fps.  Adjust -r as needed.
-	cmd := exec.Command("ffmpeg",
-		"-i", videoPath,
-		"-r", "1", // Frames per second
-		"-q:v", "2", // JPEG quality (2 is high)
-		fmt.Sprintf("%s/frame_%%04d.jpg", tempDir),
-	)
-	output, err := cmd.CombinedOutput()
-	if err != nil {
-		os.RemoveAll(tempDir)
-		return nil, fmt.Errorf("error extracting frames: %w, output: %s", err, string(output))
 	}
 
-	// Get list of extracted frame files
-	var framePaths []string
-	filepath.WalkDir(tempDir, func(path string, d fs.DirEntry, err error) error {
-		if err != nil {
-			return err
-		}
-		if !d.IsDir() && strings.HasSuffix(d.Name(), ".jpg") {
-			framePaths = append(framePaths, path)
+	// Transcripts are streamed to disk in chunk order as chunks finish; the summary is written at the end.
+	writers := map[int]*output.TranscriptWriter{}
+	onChunk := func(video *pipeline.VideoResult, chunk pipeline.ChunkResult) {
+		if !opts.formats[formatText] {
+			return
 		}
-		return nil
-	})
-
-	return framePaths, nil
-}
-
-// transcribeFramesTesseractCLI function
-func transcribeFramesTesseractCLI(framePaths []string) (string, error) {
-	var combinedTranscript strings.Builder
-	var wg sync.WaitGroup
-	frameResults := make(chan struct {
-		Text  string
-		Error error
-	}, len(framePaths)) // Buffered channel for results
-
-	// Limit concurrency to the number of CPUs (or a reasonable limit)
-	numWorkers := runtime.NumCPU()
-	if numWorkers > 8 { //  cap it to 8 for now to avoid too many subprocesses
-		numWorkers = 8
-	}
-	guard := make(chan struct{}, numWorkers) // Semaphore
-
-	for _, framePath := range framePaths {
-		wg.Add(1)
-		guard <- struct{}{} // Acquire a slot
-
-		go func(fp string) {
-			defer wg.Done()
-			defer func() { <-guard }() // Release the slot
-
-			// Open the image file
-			imgFile, err := os.Open(fp)
-			if err != nil {
-				frameResults <- struct {
-					Text  string
-					Error error
-				}{"", fmt.Errorf("error opening image file %s: %w", fp, err)}
-				return
-			}
-
-			// Decode the image
-			img, _, err := image.Decode(imgFile)
-			imgFile.Close() // Close immediately after decoding
-			if err != nil {
-				frameResults <- struct {
-					Text  string
-					Error error
-				}{"", fmt.Errorf("error decoding image file %s: %w", fp, err)}
-				return
-			}
-
-			// Convert to JPEG
-			buf := new(bytes.Buffer)
-			if err := jpeg.Encode(buf, img, &jpeg.Options{Quality: 90}); err != nil {
-				frameResults <- struct {
-					Text  string
-					Error error
-				}{"", fmt.Errorf("error encoding image to JPEG: %w", err)}
-				return
-			}
-			jpegBytes := buf.Bytes()
-
-			tempFile, err := os.CreateTemp("", "ocr_*.jpg")
-			if err != nil {
-				frameResults <- struct {
-					Text  string
-					Error error
-				}{"", fmt.Errorf("error creating temp file: %w", err)}
-				return
-			}
-			tempFilePath := tempFile.Name()
-			defer os.Remove(tempFilePath)
-
-			_, err = tempFile.Write(jpegBytes)
+		w, ok := writers[video.VideoIndex]
+		if !ok {
+			fmt.Println("Creating output files for video:", video.VideoPath)
+			dir, err := layout.Dir(video.VideoPath)
 			if err != nil {
-				tempFile.Close() // Close before removing
-				frameResults <- struct {
-					Text  string
-					Error error
-				}{"", fmt.Errorf("error writing to temp file: %w", err)}
+				log.Println(err)
 				return
 			}
-			if err := tempFile.Close(); err != nil {
-				frameResults <- struct {
-					Text  string
-					Error error
-				}{"", fmt.Errorf("error closing temp file: %w", err)}
-				return
-			}
-
-			cmd := exec.Command("tesseract", tempFilePath, "stdout")
-			var stdout, stderr bytes.Buffer
-			cmd.Stdout = &stdout
-			cmd.Stderr = &stderr
-
-			err = cmd.Run()
-			if err != nil {
-				frameResults <- struct {
-					Text  string
-					Error error
-				}{"", fmt.Errorf("error running tesseract on %s: %w, stderr: %s", fp, err, stderr.String())}
+			if w, err = output.NewTranscriptWriter(video, dir); err != nil {
+				log.Println(err)
 				return
 			}
-
-			frameResults <- struct {
-				Text  string
-				Error error
-			}{stdout.String(), nil}
-
-		}(framePath)
-	}
-
-	wg.Wait()           // Wait for all goroutines to finish
-	close(frameResults) // Close the channel - no more results coming
-
-	// Collect results from the channel
-	for result := range frameResults {
-		if result.Error != nil {
-			log.Println(result.Error) // Log individual errors
-			continue                  // Skip frames with errors
+			writers[video.VideoIndex] = w
 		}
-		combinedTranscript.WriteString(result.Text)
-		combinedTranscript.WriteString("\n")
-	}
-
-	return combinedTranscript.String(), nil
-}
-
-// transcribeVideoLLM function
-func transcribeVideoLLM(ctx context.Context, client *genai.Client, model *genai.GenerativeModel, videoPath string, videoIndex int, chunkNum int) (string, error) {
-	uploadedFile, err := client.UploadFileFromPath(ctx, videoPath, nil)
-	if err != nil {
-		// If LLM fails, fall back to Tesseract
-		fmt.Printf("Chunk %d for video %d: LLM upload failed, falling back to Tesseract...\n", chunkNum, videoIndex)
-		framePaths, err := extractFrames(videoPath, videoIndex, chunkNum)
-		if err != nil {
-			return "", fmt.Errorf("error extracting frames for video %d chunk %d: %w", videoIndex, chunkNum, err)
-		}
-		transcript, err := transcribeFramesTesseractCLI(framePaths)
-		if err != nil {
-			return "", fmt.Errorf("error transcribing frames with Tesseract for video %d chunk %d: %w", videoIndex, chunkNum, err)
-		}
-
-		// Cleanup extracted frames.
-		if len(framePaths) > 0 {
-			os.RemoveAll(filepath.Dir(framePaths[0]))
+		if err := w.WriteChunk(chunk); err != nil {
+			log.Println(err)
 		}
-		return transcript, nil
-
-	}
-
-	fmt.Println("Waiting for 30 seconds after file upload to ensure file activation...")
-	time.Sleep(30 * time.Second) // Wait for file to be ready
-	defer func() { client.DeleteFile(ctx, uploadedFile.Name) }()
-
-	fmt.Printf("Chunk %d for video %d: Video chunk uploaded as: %s\n", chunkNum, videoIndex, uploadedFile.URI)
-
-	promptList := []genai.Part{
-		genai.Text("## Task Description\nAnalyze the video and provide a detailed raw transcription of text displayed in the video."),
-		genai.FileData{URI: uploadedFile.URI},
 	}
-	videoTranscript := sentLlmPrompt(model, promptList, ctx, nil, videoIndex) // No file writing here
-
-	if videoTranscript == "" {
-		// If LLM transcription fails, fall back to Tesseract
-		fmt.Printf("Chunk %d for video %d: LLM transcription failed, falling back to Tesseract...\n", chunkNum, videoIndex)
-		framePaths, err := extractFrames(videoPath, videoIndex, chunkNum)
-		if err != nil {
-			return "", fmt.Errorf("error extracting frames for video %d chunk %d: %w", videoIndex, chunkNum, err)
-		}
-		transcript, err := transcribeFramesTesseractCLI(framePaths)
-		// Cleanup extracted frames.
-		if len(framePaths) > 0 {
-			os.RemoveAll(filepath.Dir(framePaths[0]))
-		}
-		if err != nil {
-			return "", fmt.Errorf("error transcribing frames with Tesseract for video %d chunk %d: %w", videoIndex, chunkNum, err)
+	write := func(ctx context.Context, result *pipeline.VideoResult, dir string) {
+		if w, ok := writers[result.VideoIndex]; ok {
+			if err := w.Close(); err != nil {
+				log.Println(err)
+			}
+			delete(writers, result.VideoIndex)
 		}
-		return transcript, nil
+		writeOutputs(ctx, result, dir, opts)
 	}
-
-	fmt.Printf("Chunk %d for video %d: Video transcribed by LLM.\n", chunkNum, videoIndex)
-
-	return videoTranscript, nil
-}
-
-// processChunk function
-func processChunk(chunkData ChunkData, client *genai.Client, model *genai.GenerativeModel, ctx context.Context, errorChannel chan<- error, whisperCLIPath string, whisperModelPath string, whisperThreads int, whisperLanguage string, audioOutputFile, videoOutputFile *os.File) {
-	chunk := chunkData
-
-	if chunk.Err != nil {
-		errorChannel <- chunk.Err
-		return
-	}
-
-	fmt.Printf("Processing chunk %d for video %d...\n", chunk.ChunkNum, chunk.VideoIndex)
-	defer fmt.Printf("Finished processing chunk %d for video %d.\n", chunk.ChunkNum, chunk.VideoIndex)
-
-	var wg sync.WaitGroup
-	wg.Add(2) // We have two goroutines: audio and video transcription
-
-	var audioTranscript string
-	var audioErr error
-	go func() {
-		defer wg.Done()
-		audioTranscript, audioErr = transcribeAudioWhisperCLI(chunk.AudioPath, whisperCLIPath, whisperModelPath, chunk.VideoIndex, chunk.ChunkNum, whisperThreads, whisperLanguage)
-		if audioErr != nil {
-			errorChannel <- fmt.Errorf("error transcribing audio for video %d chunk %d: %w", chunk.VideoIndex, chunk.ChunkNum, audioErr)
-			audioTranscript = fmt.Sprintf("Audio transcription failed for video %d chunk %d.", chunk.VideoIndex, chunk.ChunkNum)
-		}
-		// Write to audio output file *immediately*
-		_, err := fmt.Fprintf(audioOutputFile, "Video Index: %d, Chunk: %d\n%s\n", chunk.VideoIndex, chunk.ChunkNum, audioTranscript)
-		if err != nil {
-			errorChannel <- fmt.Errorf("error writing to audio file for video %d chunk %d: %v", chunk.VideoIndex, chunk.ChunkNum, err)
-		}
-		fmt.Printf("Chunk %d for video %d: Audio transcribed and written to audio output file.\n", chunk.ChunkNum, chunk.VideoIndex)
-		os.Remove(chunk.AudioPath) // Delete audio chunk
-	}()
-
-	var videoTranscript string
-	var videoErr error
-	go func() {
-		defer wg.Done()
-		videoTranscript, videoErr = transcribeVideoLLM(ctx, client, model, chunk.VideoPath, chunk.VideoIndex, chunk.ChunkNum)
-		if videoErr != nil {
-			errorChannel <- fmt.Errorf("error transcribing video for video %d chunk %d: %w", chunk.VideoIndex, chunk.ChunkNum, videoErr)
-			videoTranscript = fmt.Sprintf("Video transcription failed for video %d chunk %d.", chunk.VideoIndex, chunk.ChunkNum)
-		}
-		// Write to video output file *immediately*
-		_, err := fmt.Fprintf(videoOutputFile, "Video Index: %d, Chunk: %d\n%s\n", chunk.VideoIndex, chunk.ChunkNum, videoTranscript)
-		if err != nil {
-			errorChannel <- fmt.Errorf("error writing to video file for video %d chunk %d: %v", chunk.VideoIndex, chunk.ChunkNum, err)
-		}
-		fmt.Printf("Chunk %d for video %d: Video transcribed and written to video output file.\n", chunk.ChunkNum, chunk.VideoIndex)
-		os.Remove(chunk.VideoPath) // Delete video chunk
-	}()
-
-	wg.Wait() // Wait for both goroutines to complete
-
+	return runPipeline(ctx, cfg, inputPath, layout, onChunk, write)
 }
 
-func VideoSummary(llm string, apiKey string, chunkDuration int, whisperCLIPath string, whisperModelPath string, whisperThreads int, whisperLanguage string, inputPath string) error {
+// runPipeline runs the pipeline over inputPath and calls write with each finished video and its
+// output directory; onChunk, if not nil, becomes the pipeline's OnChunk. It returns
+// errPartialFailure if any video had failures and ctx.Err() if the run was interrupted.
+func runPipeline(ctx context.Context, cfg pipeline.Config, inputPath string, layout *output.Layout, onChunk func(*pipeline.VideoResult, pipeline.ChunkResult), write func(context.Context, *pipeline.VideoResult, string)) error {
 	runtime.GOMAXPROCS(runtime.NumCPU())
 
-	client, model, ctx := setLlmApi(llm, apiKey)
-	defer client.Close()
-
-	errorChannel := make(chan error, 10) // Buffered channel
-
-	var videoPaths []string
-	fileInfo, err := os.Stat(inputPath)
+	cfg.Logger = progressLog
+	ctx = progress.NewContext(ctx, progressLog)
+	p, err := pipeline.New(ctx, cfg)
 	if err != nil {
-		log.Fatalf("Error accessing input path: %v\n", err)
+		return err
 	}
+	defer p.Close()
 
-	if fileInfo.IsDir() {
-		fmt.Println("Processing folder:", inputPath)
-		err = filepath.WalkDir(inputPath, func(path string, d fs.DirEntry, err error) error {
-			if err != nil {
-				return err
-			}
-			if !d.IsDir() && isVideoFile(path) {
-				videoPaths = append(videoPaths, path)
-			}
-			return nil
-		})
+	p.OnChunk = onChunk
+	p.OnVideo = func(result *pipeline.VideoResult) {
+		dir, err := layout.Dir(result.VideoPath)
 		if err != nil {
-			log.Fatalf("Error walking directory: %v\n", err)
+			log.Println(err)
+			return
 		}
-	} else {
-		fmt.Println("Processing single file:", inputPath)
-		if isVideoFile(inputPath) {
-			videoPaths = append(videoPaths, inputPath)
-		} else {
-			log.Println("Warning: Input path is not a video file:", inputPath)
+		fmt.Println("Writing outputs to:", dir)
+		write(ctx, result, dir)
+		for _, f := range result.Failures {
+			log.Printf("Error [%s]: %v\n", f.Kind(), f)
 		}
 	}
 
-	if len(videoPaths) == 0 {
-		fmt.Println("No video files found to process.")
-		return nil
+	results, err := p.Run(ctx, inputPath)
+	if err != nil {
+		return err
 	}
-
-	for videoIndex, videoPath := range videoPaths {
-		baseName := strings.TrimSuffix(filepath.Base(videoPath), filepath.Ext(videoPath))
-		outputFileName := baseName + "_output.txt"
-		audioOutputFileName := baseName + "_audio_output.txt"
-		videoOutputFileName := baseName + "_video_output.txt"
-
-		fmt.Printf("\n--- START PROCESSING VIDEO %d: %s ---\n", videoIndex+1, videoPath)
-		fmt.Println("Creating output files for video:", videoPath)
-
-		outputFile, err := os.Create(outputFileName)
-		if err != nil {
-			log.Fatalf("Error creating output file for video %s: %v\n", videoPath, err)
-			continue // Continue to the next video
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	failed := 0
+	for _, result := range results {
+		if result.Failed() {
+			failed++
+			fmt.Printf("Video %d (%s): %d failures, see its result JSON and report.\n", result.VideoIndex, result.VideoPath, len(result.Failures))
 		}
-		defer outputFile.Close()
+	}
+	fmt.Println("Exiting.")
+	if failed > 0 {
+		return fmt.Errorf("%d of %d videos: %w", failed, len(results), errPartialFailure)
+	}
+	return nil
+}
 
-		audioOutputFile, err := os.Create(audioOutputFileName)
-		if err != nil {
-			log.Fatalf("Error creating audio output file for video %s: %v\n", videoPath, err)
-			continue
+// writeOutputs writes the summary, JSON result, report and subtitles of a finished video to dir,
+// as far as they are among opts.formats. Embedding is skipped once ctx is cancelled.
+func writeOutputs(ctx context.Context, result *pipeline.VideoResult, dir string, opts runOptions) {
+	formats := opts.formats
+	if formats[formatText] {
+		if err := output.WriteSummary(result, dir); err != nil {
+			log.Println(err)
 		}
-		defer audioOutputFile.Close()
-
-		videoOutputFile, err := os.Create(videoOutputFileName)
-		if err != nil {
-			log.Fatalf("Error creating video output file for video %s: %v\n", videoPath, err)
-			continue
+	}
+	if formats[formatJSON] {
+		if _, err := output.WriteJSON(result, dir); err != nil {
+			log.Println(err)
 		}
-		defer videoOutputFile.Close()
-		fmt.Println("Output files created for video:", videoPath)
-
-		fmt.Println("Chunking video sequentially...")
-		chunks, err := chunkVideo(videoPath, chunkDuration, videoIndex+1, baseName)
-		if err != nil {
-			log.Printf("Error chunking video %s: %v\n", videoPath, err)
-			continue
+	}
+	if formats[formatReport] || formats[formatPDF] {
+		if _, err := output.WriteReport(ctx, result, dir, opts.reportTemplate, formats[formatPDF]); err != nil {
+			log.Println(err)
 		}
-		fmt.Println("Video chunking complete.")
-
-		fmt.Println("Processing video chunks in parallel...")
-		// No more slices needed here
-
-		for _, chunkData := range chunks {
-			processChunk(chunkData, client, model, ctx, errorChannel, whisperCLIPath, whisperModelPath, whisperThreads, whisperLanguage, audioOutputFile, videoOutputFile)
+	}
+	var subtitleFormats []subtitle.Format
+	for _, f := range []subtitle.Format{subtitle.SRT, subtitle.VTT} {
+		if formats[string(f)] {
+			subtitleFormats = append(subtitleFormats, f)
 		}
-
-		fmt.Println("All video chunks processed. Sending combined prompt to LLM...")
-
-		// Read the *entire* content of the audio and video files.
-		audioContent, err := os.ReadFile(audioOutputFileName)
-		if err != nil {
-			log.Printf("Error reading audio output file: %v", err)
-			continue // Crucial: Continue to the next video if reading fails
+	}
+	var subtitlePaths []string
+	if len(subtitleFormats) > 0 {
+		var err error
+		if subtitlePaths, err = output.WriteSubtitles(result, dir, subtitleFormats, opts.subtitles); err != nil {
+			log.Println(err)
 		}
-		videoContent, err := os.ReadFile(videoOutputFileName)
-		if err != nil {
-			log.Printf("Error reading video output file: %v", err)
-			continue
+	}
+	if opts.embedMode != output.EmbedNone && len(subtitlePaths) > 0 && ctx.Err() == nil {
+		fmt.Println("Embedding subtitles into a copy of:", result.VideoPath)
+		if _, err := output.EmbedSubtitles(ctx, result, dir, subtitlePaths[0], opts.embedMode); err != nil {
+			log.Println(err)
 		}
-		combinedAudioTranscript := string(audioContent) // Convert to string
-		combinedVideoTranscript := string(videoContent)
-
-		combinedPromptText := fmt.Sprintf(`Here is a raw transcription of a video. Your task is to refine it into a well-structured, human-like summary with explanations while keeping all the original details. Analyze the lecture provided in the audio transcription and video text.  Identify the main topic, key arguments, supporting evidence, and any examples used.  Explain the lecture in a structured way, highlighting the connections between different ideas.  Use information from both the audio transcription and video text to create a comprehensive explanation, also use timestamp to help us correlate with the audio transcript:
+	}
+}
 
-    --- RAW TRANSCRIPTION of Audio ---
-    %s
+// progName is the program name used in usage messages.
+const progName = "videoSummaryGo"
 
-    --- RAW TRANSCRIPTION of Video Text ---
-    %s
+// errUsage is returned by a command whose arguments were invalid; its usage has been printed.
+var errUsage = errors.New("invalid arguments")
 
-    Please rewrite it clearly with explanations where needed, ensuring it's easy to read and understand.`, combinedAudioTranscript, combinedVideoTranscript)
+// command is a subcommand of the CLI.
+type command struct {
+	name    string
+	summary string
+	run     func(ctx context.Context, args []string) error
+}
 
-		combinedPrompt := []genai.Part{
-			genai.Text(combinedPromptText),
-		}
+// commands lists the subcommands in the order they are shown in the usage message.
+var commands = []command{
+	{"run", "transcribe, summarize and write all outputs (the default pipeline)", runCmd},
+	{"transcribe", "transcribe audio only and write transcripts and subtitles", transcribeCmd},
+	{"ocr", "transcribe on-screen text only", ocrCmd},
+	{"summarize", "summarize again from existing JSON results", summarizeCmd},
+	{"probe", "print ffprobe information about videos", probeCmd},
+	{"doctor", "check tools, models, credentials and disk space", doctorCmd},
+	{"config", "'config show' prints the effective configuration", configCmd},
+}
 
-		sentLlmPrompt(model, combinedPrompt, ctx, outputFile, videoIndex+1) // Now passing the file
-		fmt.Printf("\n--- FINISHED PROCESSING VIDEO %d: %s ---\n", videoIndex+1, videoPath)
-		fmt.Fprintf(outputFile, "\n--- VIDEO %d PROCESSING COMPLETE ---\n\n", videoIndex+1)
-		fmt.Fprintf(audioOutputFile, "\n--- VIDEO %d PROCESSING COMPLETE ---\n\n", videoIndex+1)
-		fmt.Fprintf(videoOutputFile, "\n--- VIDEO %d PROCESSING COMPLETE ---\n\n", videoIndex+1)
-	}
-	close(errorChannel) // Close *after* the loop, *before* reading
-	for err := range errorChannel {
-		log.Println("Error from goroutine:", err)
+// usage prints the top-level usage message.
+func usage() {
+	fmt.Fprintf(os.Stderr, "Usage: %s <command> [flags] [arguments]\n\nCommands:\n", progName)
+	for _, c := range commands {
+		fmt.Fprintf(os.Stderr, "  %-11s %s\n", c.name, c.summary)
 	}
+	fmt.Fprintf(os.Stderr, "\nRun '%s <command> --help' for the flags of a command.\n", progName)
+}
 
-	fmt.Println("\nAll videos processing complete.")
-	fmt.Println("Exiting.")
+// findCommand returns the command called name, or nil.
+func findCommand(name string) *command {
+	for i := range commands {
+		if commands[i].name == name {
+			return &commands[i]
+		}
+	}
 	return nil
+}
 
+// exitCode maps the error returned by a command to the process exit status.
+func exitCode(err error) int {
+	switch {
+	case err == nil, errors.Is(err, flag.ErrHelp):
+		return 0
+	case errors.Is(err, context.Canceled):
+		return exitInterrupted
+	case errors.Is(err, errPartialFailure):
+		return exitPartial
+	default:
+		return exitFailure
+	}
 }
 
-// main function
 func main() {
-	// Use all available CPUs
+	// API keys registered by the llm package are masked in every log line.
+	log.SetOutput(secret.Writer(os.Stderr))
 
-	if len(os.Args) != 9 {
-		fmt.Println("Usage: program <llm_model> <api_key> <chunk_duration_seconds> <whisper_cli_path> <whisper_model_path> <whisper_threads> <whisper_language> <video_path_or_folder>")
-		os.Exit(1)
-	}
-	llm := os.Args[1]
-	apiKey := os.Args[2]
-	chunkDuration, err := strconv.Atoi(os.Args[3])
-	if err != nil {
-		log.Fatalf("Invalid chunk duration: %v\n", err)
+	args := os.Args[1:]
+	if len(args) == 0 {
+		usage()
+		os.Exit(exitFailure)
 	}
-	whisperCLIPath := os.Args[4]
-	whisperModelPath := os.Args[5]
-	whisperThreads, err := strconv.Atoi(os.Args[6])
-	if err != nil {
-		log.Fatalf("Invalid whisper threads: %v\n", err)
+	switch args[0] {
+	case "help", "-h", "-help", "--help":
+		if len(args) > 1 && findCommand(args[1]) != nil {
+			os.Exit(exitCode(findCommand(args[1]).run(context.Background(), []string{"--help"})))
+		}
+		usage()
+		return
 	}
-	whisperLanguage := os.Args[7]
-	inputPath := os.Args[8]
 
-	err = VideoSummary(llm, apiKey, chunkDuration, whisperCLIPath, whisperModelPath, whisperThreads, whisperLanguage, inputPath)
-	if err != nil {
-		return
+	cmd := findCommand(args[0])
+	switch {
+	case cmd != nil:
+		args = args[1:]
+	case strings.HasPrefix(args[0], "-") || len(args) >= 8:
+		// Invocations from before subcommands existed run the full pipeline.
+		cmd = findCommand("run")
+	default:
+		fmt.Fprintf(os.Stderr, "Unknown command %q.\n\n", args[0])
+		usage()
+		os.Exit(exitFailure)
 	}
-}
 
-// isVideoFile function
-func isVideoFile(path string) bool {
-	ext := strings.ToLower(filepath.Ext(path))
-	videoExtensions := []string{".mp4", ".mov", ".avi", ".wmv", ".mkv", ".flv", ".webm", ".mpeg", ".mpg"}
-	for _, vext := range videoExtensions {
-		if ext == vext {
-			return true
-		}
+	// The first SIGINT/SIGTERM cancels the run gracefully; a second one kills the process.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-ctx.Done()
+		stop()
+		fmt.Println("\nInterrupt received: finishing running chunks and writing partial results. Press Ctrl-C again to quit immediately.")
+	}()
+
+	err := cmd.run(ctx, args)
+	switch {
+	case err == nil, errors.Is(err, flag.ErrHelp), errors.Is(err, errUsage):
+	case errors.Is(err, context.Canceled):
+		log.Println("Interrupted; re-run with --resume to continue.")
+	default:
+		log.Println(err)
 	}
-	return false
+	os.Exit(exitCode(err))
 }
@@ -0,0 +1,134 @@
+package media
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/utkarsh-cpu/videoSummaryGo/failure"
+	"github.com/utkarsh-cpu/videoSummaryGo/progress"
+)
+
+// ChunkData holds the paths of one extracted video/audio chunk.
+type ChunkData struct {
+	VideoPath  string
+	AudioPath  string
+	ChunkNum   int
+	Err        error
+	VideoIndex int
+	BaseName   string
+	// Start and End are the chunk's position in the source video.
+	Start time.Duration
+	End   time.Duration
+	// TempDir is the directory holding the chunk files; callers remove it when done.
+	TempDir string
+}
+
+// ChunkVideo splits videoPath into chunkDuration-second video (no audio) and WAV audio chunks.
+// With a positive silenceWindow each cut is moved to the nearest pause in the audio within
+// silenceWindow of it, so chunks vary in length and words are not cut in half; Start and End
+// record where each chunk actually lies. Videos whose silences cannot be detected, e.g. because
+// they have no audio, are cut at fixed intervals.
+// Cancelling ctx kills the running ffmpeg and removes the chunks created so far.
+func ChunkVideo(ctx context.Context, videoPath string, chunkDuration int, silenceWindow time.Duration, videoIndex int, baseName string) ([]ChunkData, error) {
+	_, err := exec.LookPath("ffmpeg")
+	if err != nil {
+		return nil, failure.Wrap(failure.ToolMissing, fmt.Errorf("ffmpeg not found in PATH: %w", err))
+	}
+
+	tempDir, err := os.MkdirTemp("", "video_chunks")
+	if err != nil {
+		return nil, fmt.Errorf("error creating temporary directory: %w", err)
+	}
+
+	cmd := exec.CommandContext(ctx, "ffprobe", "-v", "error", "-show_entries", "format=duration", "-of", "default=noprint_wrappers=1:nokey=1", videoPath)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		os.RemoveAll(tempDir)
+		return nil, failure.Command(failure.FFmpeg, fmt.Errorf("error getting video duration: %w, output: %s", err, string(output)))
+	}
+	duration, err := strconv.ParseFloat(strings.TrimSpace(string(output)), 64)
+	if err != nil {
+		os.RemoveAll(tempDir)
+		return nil, fmt.Errorf("error parsing video duration: %w", err)
+	}
+	videoDuration := time.Duration(duration * float64(time.Second))
+	chunkLength := time.Duration(chunkDuration) * time.Second
+
+	var cuts []time.Duration
+	if silenceWindow > 0 {
+		silences, err := DetectSilences(ctx, videoPath, videoDuration)
+		switch {
+		case ctx.Err() != nil:
+			os.RemoveAll(tempDir)
+			return nil, ctx.Err()
+		case err != nil:
+			progress.Printf(ctx, "Warning: cutting video %d at fixed intervals: %v", videoIndex, err)
+			silenceWindow = 0
+		default:
+			cuts = silenceCuts(videoDuration, chunkLength, silenceWindow, silences)
+		}
+	}
+	if silenceWindow <= 0 {
+		numChunks := int(duration / float64(chunkDuration))
+		if int(duration)%chunkDuration != 0 {
+			numChunks++
+		}
+		for i := 0; i < numChunks; i++ {
+			cuts = append(cuts, time.Duration(i)*chunkLength)
+		}
+	}
+
+	var chunks []ChunkData
+
+	for i, start := range cuts {
+		// Fixed chunks all ask for chunkDuration seconds; silence-aligned ones end at the next cut.
+		end := min(start+chunkLength, videoDuration)
+		length := chunkLength
+		if silenceWindow > 0 {
+			end = videoDuration
+			if i+1 < len(cuts) {
+				end = cuts[i+1]
+			}
+			length = end - start
+		}
+		chunkVideoPath := fmt.Sprintf("%s/chunk_%d_video_%d.mp4", tempDir, i, videoIndex)
+		chunkAudioPath := fmt.Sprintf("%s/chunk_%d_video_%d.wav", tempDir, i, videoIndex)
+		if err := ctx.Err(); err != nil {
+			os.RemoveAll(tempDir)
+			return nil, err
+		}
+
+		cmd := exec.CommandContext(ctx, "ffmpeg",
+			"-ss", seconds(start),
+			"-i", videoPath,
+			"-t", seconds(length),
+			"-c", "copy",
+			"-an", chunkVideoPath,
+			"-ss", seconds(start),
+			"-i", videoPath,
+			"-t", seconds(length),
+			"-vn",
+			"-acodec", "pcm_s16le", // 16-bit WAV audio
+			chunkAudioPath,
+		)
+
+		output, err = cmd.CombinedOutput()
+		if err != nil {
+			os.RemoveAll(tempDir)
+			return nil, failure.Command(failure.FFmpeg, fmt.Errorf("error creating video chunk %d for video %d: %w, output: %s", i, videoIndex, err, string(output)))
+		}
+		chunks = append(chunks, ChunkData{VideoPath: chunkVideoPath, AudioPath: chunkAudioPath, ChunkNum: i, VideoIndex: videoIndex, BaseName: baseName, Start: start, End: end, TempDir: tempDir})
+	}
+
+	return chunks, nil
+}
+
+// seconds formats d as an ffmpeg time in seconds, e.g. "60" or "61.25".
+func seconds(d time.Duration) string {
+	return strconv.FormatFloat(d.Round(time.Millisecond).Seconds(), 'f', -1, 64)
+}
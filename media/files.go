@@ -0,0 +1,57 @@
+package media
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// IsVideoFile reports whether path has a known video extension.
+func IsVideoFile(path string) bool {
+	ext := strings.ToLower(filepath.Ext(path))
+	videoExtensions := []string{".mp4", ".mov", ".avi", ".wmv", ".mkv", ".flv", ".webm", ".mpeg", ".mpg"}
+	for _, vext := range videoExtensions {
+		if ext == vext {
+			return true
+		}
+	}
+	return false
+}
+
+// FindVideos returns inputPath if it is a video file, or every video file below it if it is a folder.
+// Any other file is an error.
+func FindVideos(inputPath string) ([]string, error) {
+	var videoPaths []string
+	fileInfo, err := os.Stat(inputPath)
+	if err != nil {
+		return nil, fmt.Errorf("error accessing input path: %w", err)
+	}
+
+	if fileInfo.IsDir() {
+		err = filepath.WalkDir(inputPath, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if !d.IsDir() && IsVideoFile(path) {
+				videoPaths = append(videoPaths, path)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("error walking directory: %w", err)
+		}
+	} else {
+		if !IsVideoFile(inputPath) {
+			return nil, fmt.Errorf("input path is not a video file: %s", inputPath)
+		}
+		videoPaths = append(videoPaths, inputPath)
+	}
+	return videoPaths, nil
+}
+
+// BaseName returns the file name of path without its extension.
+func BaseName(path string) string {
+	return strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+}
@@ -0,0 +1,55 @@
+package media
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/utkarsh-cpu/videoSummaryGo/failure"
+)
+
+// ExtractFrames writes one JPEG per second of videoPath into a new temp dir and returns their paths.
+func ExtractFrames(ctx context.Context, videoPath string, videoIndex int, chunkNum int) ([]string, error) {
+	tempDir, err := os.MkdirTemp("", fmt.Sprintf("frames_video%d_chunk%d", videoIndex, chunkNum))
+	if err != nil {
+		return nil, fmt.Errorf("error creating temporary directory for frames: %w", err)
+	}
+
+	// Extract frames at 1fps.  Adjust -r as needed.
+	cmd := exec.CommandContext(ctx, "ffmpeg",
+		"-i", videoPath,
+		"-r", "1", // Frames per second
+		"-q:v", "2", // JPEG quality (2 is high)
+		fmt.Sprintf("%s/frame_%%04d.jpg", tempDir),
+	)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		os.RemoveAll(tempDir)
+		return nil, failure.Command(failure.FFmpeg, fmt.Errorf("error extracting frames: %w, output: %s", err, string(output)))
+	}
+
+	// Get list of extracted frame files
+	var framePaths []string
+	filepath.WalkDir(tempDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() && strings.HasSuffix(d.Name(), ".jpg") {
+			framePaths = append(framePaths, path)
+		}
+		return nil
+	})
+
+	return framePaths, nil
+}
+
+// RemoveFrames deletes the directory created by ExtractFrames.
+func RemoveFrames(framePaths []string) {
+	if len(framePaths) > 0 {
+		os.RemoveAll(filepath.Dir(framePaths[0]))
+	}
+}
@@ -0,0 +1,41 @@
+package media
+
+import "strings"
+
+// iso6392 maps ISO 639-1 codes, including whisper's "jw" for Javanese, to the ISO 639-2/B codes
+// Matroska and ffmpeg expect in stream language tags. It covers every language whisper detects.
+var iso6392 = map[string]string{
+	"af": "afr", "am": "amh", "ar": "ara", "as": "asm", "az": "aze", "ba": "bak", "be": "bel",
+	"bg": "bul", "bn": "ben", "bo": "tib", "br": "bre", "bs": "bos", "ca": "cat", "cs": "cze",
+	"cy": "wel", "da": "dan", "de": "ger", "el": "gre", "en": "eng", "es": "spa", "et": "est",
+	"eu": "baq", "fa": "per", "fi": "fin", "fo": "fao", "fr": "fre", "gl": "glg", "gu": "guj",
+	"ha": "hau", "he": "heb", "hi": "hin", "hr": "hrv", "ht": "hat", "hu": "hun", "hy": "arm",
+	"id": "ind", "is": "ice", "it": "ita", "ja": "jpn", "jv": "jav", "jw": "jav", "ka": "geo",
+	"kk": "kaz", "km": "khm", "kn": "kan", "ko": "kor", "la": "lat", "lb": "ltz", "ln": "lin",
+	"lo": "lao", "lt": "lit", "lv": "lav", "mg": "mlg", "mi": "mao", "mk": "mac", "ml": "mal",
+	"mn": "mon", "mr": "mar", "ms": "may", "mt": "mlt", "my": "bur", "nb": "nob", "ne": "nep",
+	"nl": "dut", "nn": "nno", "no": "nor", "oc": "oci", "pa": "pan", "pl": "pol", "ps": "pus",
+	"pt": "por", "ro": "rum", "ru": "rus", "sa": "san", "sd": "snd", "si": "sin", "sk": "slo",
+	"sl": "slv", "sn": "sna", "so": "som", "sq": "alb", "sr": "srp", "su": "sun", "sv": "swe",
+	"sw": "swa", "ta": "tam", "te": "tel", "tg": "tgk", "th": "tha", "tk": "tuk", "tl": "tgl",
+	"tr": "tur", "tt": "tat", "uk": "ukr", "ur": "urd", "uz": "uzb", "vi": "vie", "yi": "yid",
+	"yo": "yor", "zh": "chi",
+}
+
+// languageTag returns the ISO 639-2 code for lang, an ISO 639-1 or 639-2 code optionally
+// followed by a region such as "en-US". It returns "" for codes it does not know.
+func languageTag(lang string) string {
+	lang = strings.ToLower(strings.TrimSpace(lang))
+	if i := strings.IndexAny(lang, "-_"); i >= 0 {
+		lang = lang[:i]
+	}
+	switch len(lang) {
+	case 2:
+		return iso6392[lang]
+	case 3:
+		if strings.Trim(lang, "abcdefghijklmnopqrstuvwxyz") == "" {
+			return lang
+		}
+	}
+	return ""
+}
@@ -0,0 +1,36 @@
+package media
+
+import "testing"
+
+func TestLanguageTag(t *testing.T) {
+	tests := []struct {
+		lang string
+		want string
+	}{
+		{"en", "eng"},
+		{"de", "ger"},
+		{"fr", "fre"},
+		{"zh", "chi"},
+		{"jw", "jav"},
+		{" EN ", "eng"},
+		{"pt-BR", "por"},
+		{"en_US", "eng"},
+		{"eng", "eng"},
+		{"deu", "deu"},
+		{"haw", "haw"},
+		{"", ""},
+		{"xx", ""},
+		{"auto", ""},
+		{"e1g", ""},
+	}
+	for _, tt := range tests {
+		if got := languageTag(tt.lang); got != tt.want {
+			t.Errorf("languageTag(%q) = %q, want %q", tt.lang, got, tt.want)
+		}
+	}
+	for code, tag := range iso6392 {
+		if len(code) != 2 || len(tag) != 3 {
+			t.Errorf("iso6392[%q] = %q, want a two-letter key and a three-letter code", code, tag)
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package media
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"time"
+
+	"github.com/utkarsh-cpu/videoSummaryGo/failure"
+)
+
+// ProbeInfo is the ffprobe description of a media file. Raw holds ffprobe's complete JSON output.
+type ProbeInfo struct {
+	Format struct {
+		Filename   string            `json:"filename"`
+		FormatName string            `json:"format_name"`
+		Duration   string            `json:"duration"`
+		Size       string            `json:"size"`
+		BitRate    string            `json:"bit_rate"`
+		Tags       map[string]string `json:"tags"`
+	} `json:"format"`
+	Streams []struct {
+		Index     int    `json:"index"`
+		CodecType string `json:"codec_type"`
+		CodecName string `json:"codec_name"`
+		Width     int    `json:"width,omitempty"`
+		Height    int    `json:"height,omitempty"`
+		Channels  int    `json:"channels,omitempty"`
+	} `json:"streams"`
+	Raw json.RawMessage `json:"-"`
+}
+
+// Duration returns the container duration, or zero if ffprobe did not report one.
+func (p *ProbeInfo) Duration() time.Duration {
+	seconds, err := strconv.ParseFloat(p.Format.Duration, 64)
+	if err != nil {
+		return 0
+	}
+	return time.Duration(seconds * float64(time.Second))
+}
+
+// Probe runs ffprobe on path and returns its format and stream information.
+func Probe(ctx context.Context, path string) (*ProbeInfo, error) {
+	cmd := exec.CommandContext(ctx, "ffprobe", "-v", "error", "-show_format", "-show_streams", "-of", "json", path)
+	output, err := cmd.Output()
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			return nil, failure.Command(failure.FFmpeg, fmt.Errorf("error probing %s: %w, output: %s", path, err, string(exitErr.Stderr)))
+		}
+		return nil, failure.Command(failure.FFmpeg, fmt.Errorf("error probing %s: %w", path, err))
+	}
+	info := &ProbeInfo{Raw: output}
+	if err := json.Unmarshal(output, info); err != nil {
+		return nil, fmt.Errorf("error parsing ffprobe output for %s: %w", path, err)
+	}
+	return info, nil
+}
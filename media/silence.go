@@ -0,0 +1,103 @@
+package media
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/utkarsh-cpu/videoSummaryGo/failure"
+)
+
+// Silence detection thresholds: audio quieter than SilenceNoise for at least SilenceMinDuration
+// counts as a pause between words.
+const (
+	SilenceNoise       = "-30dB"
+	SilenceMinDuration = 300 * time.Millisecond
+)
+
+// Silence is a pause in a video's audio track.
+type Silence struct {
+	Start time.Duration
+	End   time.Duration
+}
+
+// DetectSilences runs ffmpeg's silencedetect filter over the audio of videoPath, which has the
+// given duration, and returns its pauses in order. A silence still running at the end of the
+// audio ends at duration.
+func DetectSilences(ctx context.Context, videoPath string, duration time.Duration) ([]Silence, error) {
+	cmd := exec.CommandContext(ctx, "ffmpeg",
+		"-hide_banner", "-nostats",
+		"-i", videoPath,
+		"-vn",
+		"-af", fmt.Sprintf("silencedetect=noise=%s:d=%.3f", SilenceNoise, SilenceMinDuration.Seconds()),
+		"-f", "null", "-",
+	)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return nil, failure.Command(failure.FFmpeg, fmt.Errorf("error detecting silences in %s: %w, output: %s", videoPath, err, string(output)))
+	}
+	return parseSilences(string(output), duration), nil
+}
+
+// parseSilences reads the "silence_start: 12.3" and "silence_end: 13.1 | silence_duration: 0.8"
+// lines silencedetect logs.
+func parseSilences(log string, duration time.Duration) []Silence {
+	var silences []Silence
+	open := false
+	scanner := bufio.NewScanner(strings.NewReader(log))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if _, v, ok := strings.Cut(line, "silence_start: "); ok {
+			if start, ok := parseSeconds(v); ok {
+				silences = append(silences, Silence{Start: max(start, 0), End: duration})
+				open = true
+			}
+		} else if _, v, ok := strings.Cut(line, "silence_end: "); ok && open {
+			if end, ok := parseSeconds(v); ok {
+				silences[len(silences)-1].End = end
+				open = false
+			}
+		}
+	}
+	return silences
+}
+
+// parseSeconds parses the number of seconds at the start of s.
+func parseSeconds(s string) (time.Duration, bool) {
+	field, _, _ := strings.Cut(strings.TrimSpace(s), " ")
+	seconds, err := strconv.ParseFloat(field, 64)
+	if err != nil {
+		return 0, false
+	}
+	return time.Duration(seconds * float64(time.Second)), true
+}
+
+// silenceCuts returns the start of every chunk of a video of the given duration, rounded to the
+// millisecond. Each cut is placed chunkDuration after the previous one and then moved to the
+// nearest point of silence within window of it, if there is one. No cut is made once the rest of
+// the video fits into chunkDuration+window, so the last chunk is not a short remainder.
+func silenceCuts(duration time.Duration, chunkDuration time.Duration, window time.Duration, silences []Silence) []time.Duration {
+	cuts := []time.Duration{0}
+	for start := time.Duration(0); duration-start > chunkDuration+window; {
+		target := start + chunkDuration
+		cut := target.Round(time.Millisecond)
+		best := window + 1
+		for _, s := range silences {
+			point := min(max(target, s.Start), s.End).Round(time.Millisecond)
+			if d := (point - target).Abs(); d <= window && d < best && point > start {
+				cut, best = point, d
+			}
+		}
+		if cut <= start {
+			// A chunk duration below a millisecond would never advance.
+			break
+		}
+		start = cut
+		cuts = append(cuts, start)
+	}
+	return cuts
+}
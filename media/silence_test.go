@@ -0,0 +1,112 @@
+package media
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestParseSilences(t *testing.T) {
+	log := `Input #0, mov,mp4,m4a,3gp,3g2,mj2, from 'talk.mp4':
+[silencedetect @ 0x55d1c0] silence_start: 58.2
+[silencedetect @ 0x55d1c0] silence_end: 59.004 | silence_duration: 0.804
+[silencedetect @ 0x55d1c0] silence_start: -0.01
+[silencedetect @ 0x55d1c0] silence_end: 0.5 | silence_duration: 0.51
+[silencedetect @ 0x55d1c0] silence_end: 70 | silence_duration: 1
+[silencedetect @ 0x55d1c0] silence_start: 175.25
+size=N/A time=00:03:05.00 bitrate=N/A speed= 512x
+`
+	got := parseSilences(log, 185*time.Second)
+	want := []Silence{
+		{Start: 58200 * time.Millisecond, End: 59004 * time.Millisecond},
+		{Start: 0, End: 500 * time.Millisecond},
+		{Start: 175250 * time.Millisecond, End: 185 * time.Second},
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("parseSilences() = %v, want %v", got, want)
+	}
+	if got := parseSilences("no silences here\n", time.Minute); len(got) != 0 {
+		t.Errorf("parseSilences() = %v, want none", got)
+	}
+}
+
+func TestSilenceCuts(t *testing.T) {
+	s := func(start, end float64) Silence {
+		return Silence{Start: time.Duration(start * float64(time.Second)), End: time.Duration(end * float64(time.Second))}
+	}
+	sec := func(secs ...float64) []time.Duration {
+		var cuts []time.Duration
+		for _, v := range secs {
+			cuts = append(cuts, time.Duration(v*float64(time.Second)))
+		}
+		return cuts
+	}
+	tests := []struct {
+		name     string
+		duration time.Duration
+		chunk    time.Duration
+		window   time.Duration
+		silences []Silence
+		want     []time.Duration
+	}{
+		{
+			name:     "no silences cuts at fixed intervals",
+			duration: 185 * time.Second, chunk: time.Minute, window: 5 * time.Second,
+			want: sec(0, 60, 120),
+		},
+		{
+			name:     "cut inside a silence stays put",
+			duration: 100 * time.Second, chunk: time.Minute, window: 5 * time.Second,
+			silences: []Silence{s(59, 61)},
+			want:     sec(0, 60),
+		},
+		{
+			name:     "cut moves to the near edge of a later silence",
+			duration: 100 * time.Second, chunk: time.Minute, window: 5 * time.Second,
+			silences: []Silence{s(61, 70)},
+			want:     sec(0, 61),
+		},
+		{
+			name:     "cut moves to the near edge of an earlier silence",
+			duration: 100 * time.Second, chunk: time.Minute, window: 5 * time.Second,
+			silences: []Silence{s(50, 57.5)},
+			want:     sec(0, 57.5),
+		},
+		{
+			name:     "nearest silence wins",
+			duration: 100 * time.Second, chunk: time.Minute, window: 5 * time.Second,
+			silences: []Silence{s(56, 57), s(62, 63), s(64, 65)},
+			want:     sec(0, 62),
+		},
+		{
+			name:     "silences outside the window are ignored",
+			duration: 100 * time.Second, chunk: time.Minute, window: 2 * time.Second,
+			silences: []Silence{s(55, 57), s(63, 64)},
+			want:     sec(0, 60),
+		},
+		{
+			name:     "next cut is measured from the moved one",
+			duration: 180 * time.Second, chunk: time.Minute, window: 5 * time.Second,
+			silences: []Silence{s(58.6, 58.7), s(119.5, 125)},
+			want:     sec(0, 58.7, 119.5),
+		},
+		{
+			name:     "short remainder joins the last chunk",
+			duration: 124 * time.Second, chunk: time.Minute, window: 5 * time.Second,
+			want: sec(0, 60),
+		},
+		{
+			name:     "sub-millisecond chunks stop instead of looping",
+			duration: time.Second, chunk: 100 * time.Microsecond, window: 0,
+			want: sec(0),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := silenceCuts(tt.duration, tt.chunk, tt.window, tt.silences)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("silenceCuts() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
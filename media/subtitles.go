@@ -0,0 +1,81 @@
+package media
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/utkarsh-cpu/videoSummaryGo/failure"
+)
+
+// MuxSubtitles copies videoPath to outPath with subtitlePath added as a soft subtitle track.
+// The streams are not re-encoded; the subtitle codec is mov_text for .mp4/.mov outputs and
+// the native SRT/WebVTT codec otherwise (e.g. .mkv). language, an ISO 639-1 or 639-2 code, is
+// written as the track's ISO 639-2 language tag; it is optional and left out when unknown.
+func MuxSubtitles(ctx context.Context, videoPath string, subtitlePath string, outPath string, language string) error {
+	_, err := exec.LookPath("ffmpeg")
+	if err != nil {
+		return failure.Wrap(failure.ToolMissing, fmt.Errorf("ffmpeg not found in PATH: %w", err))
+	}
+
+	subtitleCodec := "srt"
+	switch strings.ToLower(filepath.Ext(outPath)) {
+	case ".mp4", ".mov", ".m4v":
+		subtitleCodec = "mov_text"
+	case ".webm":
+		subtitleCodec = "webvtt"
+	}
+
+	args := []string{
+		"-y",
+		"-i", videoPath,
+		"-i", subtitlePath,
+		"-map", "0:v",
+		"-map", "0:a?",
+		"-map", "1:0",
+		"-c", "copy",
+		"-c:s", subtitleCodec,
+	}
+	if tag := languageTag(language); tag != "" {
+		args = append(args, "-metadata:s:s:0", "language="+tag)
+	}
+	args = append(args, outPath)
+
+	cmd := exec.CommandContext(ctx, "ffmpeg", args...)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return failure.Command(failure.FFmpeg, fmt.Errorf("error muxing subtitles into %s: %w, output: %s", outPath, err, string(output)))
+	}
+	return nil
+}
+
+// BurnSubtitles re-encodes videoPath to outPath with subtitlePath rendered into the picture
+// using ffmpeg's subtitles filter. Audio is copied unchanged.
+func BurnSubtitles(ctx context.Context, videoPath string, subtitlePath string, outPath string) error {
+	_, err := exec.LookPath("ffmpeg")
+	if err != nil {
+		return failure.Wrap(failure.ToolMissing, fmt.Errorf("ffmpeg not found in PATH: %w", err))
+	}
+
+	cmd := exec.CommandContext(ctx, "ffmpeg",
+		"-y",
+		"-i", videoPath,
+		"-vf", "subtitles="+escapeFilterArg(subtitlePath),
+		"-c:a", "copy",
+		outPath,
+	)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return failure.Command(failure.FFmpeg, fmt.Errorf("error burning subtitles into %s: %w, output: %s", outPath, err, string(output)))
+	}
+	return nil
+}
+
+// escapeFilterArg escapes a path for use as a filter option value inside a filtergraph:
+// once for the option parser and once more for the filtergraph parser.
+func escapeFilterArg(s string) string {
+	s = strings.NewReplacer(`\`, `\\`, `'`, `\'`, `:`, `\:`).Replace(s)
+	return strings.NewReplacer(`\`, `\\`, `'`, `\'`, `[`, `\[`, `]`, `\]`, `,`, `\,`, `;`, `\;`).Replace(s)
+}
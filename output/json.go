@@ -0,0 +1,243 @@
+package output
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/utkarsh-cpu/videoSummaryGo/audio"
+	"github.com/utkarsh-cpu/videoSummaryGo/failure"
+	"github.com/utkarsh-cpu/videoSummaryGo/media"
+	"github.com/utkarsh-cpu/videoSummaryGo/pipeline"
+)
+
+// ResultSchemaVersion is bumped whenever ResultDocument changes incompatibly.
+const ResultSchemaVersion = 2
+
+// ResultDocument is the machine-readable result of processing one video.
+// Times and durations are in seconds.
+type ResultDocument struct {
+	SchemaVersion int               `json:"schema_version"`
+	Source        SourceDocument    `json:"source"`
+	Chunks        []ChunkDocument   `json:"chunks"`
+	Summary       string            `json:"summary"`
+	Failures      []FailureDocument `json:"failures"`
+	Timings       TimingsDocument   `json:"timings"`
+}
+
+// FailureDocument is one entry of the per-video failure report.
+type FailureDocument struct {
+	Stage   string `json:"stage"` // probe, chunking, audio, visual or summary
+	Chunk   int    `json:"chunk"` // -1 when not tied to a chunk
+	Kind    string `json:"kind"`  // failure.Kind, e.g. tool_missing, whisper, llm_quota
+	Message string `json:"message"`
+}
+
+// SourceDocument describes the input video.
+type SourceDocument struct {
+	Path       string          `json:"path"`
+	BaseName   string          `json:"base_name"`
+	VideoIndex int             `json:"video_index"`
+	Duration   float64         `json:"duration,omitempty"`
+	FFprobe    json.RawMessage `json:"ffprobe,omitempty"` // complete ffprobe -show_format -show_streams output
+}
+
+// ChunkDocument is the transcription of one chunk.
+type ChunkDocument struct {
+	Index   int             `json:"index"`
+	Start   float64         `json:"start"`
+	End     float64         `json:"end"`
+	Audio   AudioDocument   `json:"audio"`
+	Visual  VisualDocument  `json:"visual"`
+	Errors  []string        `json:"errors,omitempty"`
+	Elapsed ElapsedDocument `json:"elapsed"`
+}
+
+// ElapsedDocument is the time spent transcribing a chunk's audio and visuals.
+type ElapsedDocument struct {
+	Audio  float64 `json:"audio"`
+	Visual float64 `json:"visual"`
+}
+
+// AudioDocument is a chunk's speech transcript.
+type AudioDocument struct {
+	Language string            `json:"language,omitempty"`
+	Text     string            `json:"text"`
+	Segments []SegmentDocument `json:"segments"`
+}
+
+// SegmentDocument is one timestamped speech segment with absolute video times.
+type SegmentDocument struct {
+	Start float64 `json:"start"`
+	End   float64 `json:"end"`
+	Text  string  `json:"text"`
+}
+
+// VisualDocument is a chunk's on-screen text and the backend that produced it.
+type VisualDocument struct {
+	Backend string `json:"backend,omitempty"`
+	Text    string `json:"text"`
+}
+
+// TimingsDocument records when processing started and how long each stage took.
+type TimingsDocument struct {
+	Started  time.Time `json:"started"`
+	Chunking float64   `json:"chunking"`
+	Chunks   float64   `json:"chunks"`
+	Summary  float64   `json:"summary"`
+	Total    float64   `json:"total"`
+}
+
+// NewResultDocument converts a pipeline result to a ResultDocument.
+func NewResultDocument(result *pipeline.VideoResult) *ResultDocument {
+	doc := &ResultDocument{
+		SchemaVersion: ResultSchemaVersion,
+		Source: SourceDocument{
+			Path:       result.VideoPath,
+			BaseName:   result.BaseName,
+			VideoIndex: result.VideoIndex,
+		},
+		Chunks:   []ChunkDocument{},
+		Summary:  result.Summary,
+		Failures: failureDocuments(result.Failures),
+		Timings: TimingsDocument{
+			Started:  result.Timings.Started,
+			Chunking: result.Timings.Chunking.Seconds(),
+			Chunks:   result.Timings.Chunks.Seconds(),
+			Summary:  result.Timings.Summary.Seconds(),
+			Total:    result.Timings.Total.Seconds(),
+		},
+	}
+	if result.Probe != nil {
+		doc.Source.Duration = result.Probe.Duration().Seconds()
+		doc.Source.FFprobe = result.Probe.Raw
+	}
+	for _, c := range result.Chunks {
+		chunk := ChunkDocument{
+			Index:   c.ChunkNum,
+			Start:   c.Start.Seconds(),
+			End:     c.End.Seconds(),
+			Audio:   AudioDocument{Language: c.AudioLanguage, Text: c.AudioTranscript, Segments: []SegmentDocument{}},
+			Visual:  VisualDocument{Backend: c.VideoBackend, Text: c.VideoTranscript},
+			Elapsed: ElapsedDocument{Audio: c.AudioElapsed.Seconds(), Visual: c.VideoElapsed.Seconds()},
+		}
+		for _, s := range c.AudioSegments {
+			chunk.Audio.Segments = append(chunk.Audio.Segments, SegmentDocument{Start: s.Start.Seconds(), End: s.End.Seconds(), Text: s.Text})
+		}
+		if c.AudioErr != nil {
+			chunk.Errors = append(chunk.Errors, c.AudioErr.Error())
+		}
+		if c.VideoErr != nil {
+			chunk.Errors = append(chunk.Errors, c.VideoErr.Error())
+		}
+		doc.Chunks = append(doc.Chunks, chunk)
+	}
+	return doc
+}
+
+// WriteJSON writes the ResultDocument of result to <base>_result.json in dir and returns its path.
+func WriteJSON(result *pipeline.VideoResult, dir string) (string, error) {
+	data, err := json.MarshalIndent(NewResultDocument(result), "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("error encoding result for video %s: %w", result.VideoPath, err)
+	}
+	path := filepath.Join(dir, result.BaseName+"_result.json")
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return "", fmt.Errorf("error writing output file %s: %w", path, err)
+	}
+	return path, nil
+}
+
+// failureDocuments converts failures to documents, never returning nil.
+func failureDocuments(failures []failure.Failure) []FailureDocument {
+	out := []FailureDocument{}
+	for _, f := range failures {
+		out = append(out, FailureDocument{Stage: f.Stage, Chunk: f.Chunk, Kind: string(f.Kind()), Message: f.Err.Error()})
+	}
+	return out
+}
+
+// ReadJSON reads a result document written by WriteJSON.
+func ReadJSON(path string) (*ResultDocument, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading result file %s: %w", path, err)
+	}
+	var doc ResultDocument
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("error parsing result file %s: %w", path, err)
+	}
+	if doc.SchemaVersion != ResultSchemaVersion {
+		return nil, fmt.Errorf("result file %s has schema version %d, expected %d", path, doc.SchemaVersion, ResultSchemaVersion)
+	}
+	return &doc, nil
+}
+
+// VideoResult converts the document back to a pipeline result, so outputs can be re-rendered
+// from it. Errors are restored as plain messages tagged with their recorded kind.
+func (d *ResultDocument) VideoResult() *pipeline.VideoResult {
+	result := &pipeline.VideoResult{
+		VideoIndex: d.Source.VideoIndex,
+		VideoPath:  d.Source.Path,
+		BaseName:   d.Source.BaseName,
+		Summary:    d.Summary,
+		Timings: pipeline.Timings{
+			Started:  d.Timings.Started,
+			Chunking: seconds(d.Timings.Chunking),
+			Chunks:   seconds(d.Timings.Chunks),
+			Summary:  seconds(d.Timings.Summary),
+			Total:    seconds(d.Timings.Total),
+		},
+	}
+	if len(d.Source.FFprobe) > 0 {
+		probe := &media.ProbeInfo{Raw: d.Source.FFprobe}
+		if json.Unmarshal(d.Source.FFprobe, probe) == nil {
+			result.Probe = probe
+		}
+	}
+	chunkIndex := map[int]int{}
+	for _, c := range d.Chunks {
+		chunk := pipeline.ChunkResult{
+			ChunkNum:        c.Index,
+			Start:           seconds(c.Start),
+			End:             seconds(c.End),
+			AudioTranscript: c.Audio.Text,
+			AudioLanguage:   c.Audio.Language,
+			VideoTranscript: c.Visual.Text,
+			VideoBackend:    c.Visual.Backend,
+			AudioElapsed:    seconds(c.Elapsed.Audio),
+			VideoElapsed:    seconds(c.Elapsed.Visual),
+		}
+		for _, s := range c.Audio.Segments {
+			chunk.AudioSegments = append(chunk.AudioSegments, audio.Segment{Start: seconds(s.Start), End: seconds(s.End), Text: s.Text})
+		}
+		chunkIndex[c.Index] = len(result.Chunks)
+		result.Chunks = append(result.Chunks, chunk)
+	}
+	for _, f := range d.Failures {
+		err := &failure.Error{Kind: failure.Kind(f.Kind), Err: errors.New(f.Message)}
+		result.Failures = append(result.Failures, failure.Failure{Stage: f.Stage, Chunk: f.Chunk, Err: err})
+		i, ok := chunkIndex[f.Chunk]
+		if !ok {
+			continue
+		}
+		switch f.Stage {
+		case pipeline.StageAudio:
+			result.Chunks[i].AudioErr = err
+		case pipeline.StageVisual:
+			result.Chunks[i].VideoErr = err
+		case pipeline.StageChunking:
+			result.Chunks[i].AudioErr = err
+			result.Chunks[i].VideoErr = err
+		}
+	}
+	return result
+}
+
+// seconds converts a document time in seconds to a duration.
+func seconds(s float64) time.Duration {
+	return time.Duration(s * float64(time.Second))
+}
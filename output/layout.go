@@ -0,0 +1,103 @@
+package output
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/utkarsh-cpu/videoSummaryGo/media"
+)
+
+// Layout decides which directory each video's outputs are written to.
+//
+// A flat layout puts every file directly in Root. Otherwise each video gets its own folder
+// named after its base name; when the input is a folder its directory tree is mirrored below
+// Root, so videos with the same base name in different subdirectories do not collide. If two
+// videos in the same directory share a base name (lecture.mp4 and lecture.mkv) the second
+// folder gets the extension appended, then a numeric suffix.
+type Layout struct {
+	Root      string
+	InputRoot string // folder the videos were found in; empty for a single file
+	Flat      bool
+
+	mu      sync.Mutex
+	dirs    map[string]string // video path -> output dir
+	claimed map[string]bool
+}
+
+// NewLayout returns a per-video layout below root for videos found under input.
+func NewLayout(root string, input string) *Layout {
+	l := &Layout{Root: root}
+	if info, err := os.Stat(input); err == nil && info.IsDir() {
+		l.InputRoot = input
+	}
+	return l
+}
+
+// NewFlatLayout returns a layout that writes everything into dir.
+func NewFlatLayout(dir string) *Layout {
+	return &Layout{Root: dir, Flat: true}
+}
+
+// Dir returns the output directory for videoPath, creating it if needed. Repeated calls for the
+// same video return the same directory.
+func (l *Layout) Dir(videoPath string) (string, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.dirs == nil {
+		l.dirs = map[string]string{}
+		l.claimed = map[string]bool{}
+	}
+	if dir, ok := l.dirs[videoPath]; ok {
+		return dir, nil
+	}
+
+	dir := l.Root
+	if !l.Flat {
+		parent := l.Root
+		if l.InputRoot != "" {
+			if rel, err := filepath.Rel(l.InputRoot, filepath.Dir(videoPath)); err == nil && !strings.HasPrefix(rel, "..") {
+				parent = filepath.Join(l.Root, rel)
+			}
+		}
+		base := media.BaseName(videoPath)
+		dir = filepath.Join(parent, base)
+		if l.claimed[dir] {
+			dir = filepath.Join(parent, base+"_"+strings.TrimPrefix(strings.ToLower(filepath.Ext(videoPath)), "."))
+		}
+		for n := 2; l.claimed[dir]; n++ {
+			dir = filepath.Join(parent, fmt.Sprintf("%s_%d", base, n))
+		}
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", fmt.Errorf("error creating output directory %s: %w", dir, err)
+	}
+	l.claimed[dir] = true
+	l.dirs[videoPath] = dir
+	return dir, nil
+}
+
+// ManifestPath returns the checkpoint manifest path of videoPath, <base>_manifest.json in its
+// output directory, or "" if the directory cannot be created.
+func (l *Layout) ManifestPath(videoPath string) string {
+	return l.manifestPath(videoPath, "_manifest.json")
+}
+
+// CommandManifestPath returns a ManifestPath for the checkpoints of a partial command such as
+// "transcribe": <base>_<command>_manifest.json, so they never mix with those of a full run.
+func (l *Layout) CommandManifestPath(command string) func(videoPath string) string {
+	return func(videoPath string) string {
+		return l.manifestPath(videoPath, "_"+command+"_manifest.json")
+	}
+}
+
+// manifestPath returns <base><suffix> in the output directory of videoPath, or "".
+func (l *Layout) manifestPath(videoPath string, suffix string) string {
+	dir, err := l.Dir(videoPath)
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, media.BaseName(videoPath)+suffix)
+}
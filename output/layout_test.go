@@ -0,0 +1,25 @@
+package output
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestManifestPaths(t *testing.T) {
+	dir := t.TempDir()
+	l := NewFlatLayout(dir)
+	video := filepath.Join("videos", "lecture.mp4")
+	tests := []struct {
+		path func(string) string
+		want string
+	}{
+		{l.ManifestPath, "lecture_manifest.json"},
+		{l.CommandManifestPath("transcribe"), "lecture_transcribe_manifest.json"},
+		{l.CommandManifestPath("ocr"), "lecture_ocr_manifest.json"},
+	}
+	for _, tt := range tests {
+		if got, want := tt.path(video), filepath.Join(dir, tt.want); got != want {
+			t.Errorf("manifest path = %q, want %q", got, want)
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package output
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"text/template"
+
+	"github.com/utkarsh-cpu/videoSummaryGo/pipeline"
+	"github.com/utkarsh-cpu/videoSummaryGo/progress"
+	"github.com/utkarsh-cpu/videoSummaryGo/report"
+)
+
+// WriteReport renders the Markdown report of result with tmpl (the built-in template when nil)
+// to <base>_report.md in dir and, when pdf is set, the same report to <base>_report.pdf.
+// It returns the paths written. Text the PDF cannot show is reported through ctx's progress Logger.
+func WriteReport(ctx context.Context, result *pipeline.VideoResult, dir string, tmpl *template.Template, pdf bool) ([]string, error) {
+	data := report.NewData(result)
+	var markdown bytes.Buffer
+	if err := report.RenderMarkdown(&markdown, data, tmpl); err != nil {
+		return nil, err
+	}
+
+	mdPath := filepath.Join(dir, result.BaseName+"_report.md")
+	if err := os.WriteFile(mdPath, markdown.Bytes(), 0o644); err != nil {
+		return nil, fmt.Errorf("error writing output file %s: %w", mdPath, err)
+	}
+	paths := []string{mdPath}
+	if !pdf {
+		return paths, nil
+	}
+
+	pdfPath := filepath.Join(dir, result.BaseName+"_report.pdf")
+	f, err := os.Create(pdfPath)
+	if err != nil {
+		return paths, fmt.Errorf("error creating output file %s: %w", pdfPath, err)
+	}
+	err = report.RenderPDF(f, markdown.String(), data.Title)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return paths, err
+	}
+	if missing := report.Unrepresentable(markdown.String()); len(missing) > 0 {
+		progress.Printf(ctx, "Warning: %d characters of %s are outside the PDF fonts and shown as dots, e.g. %q; %s has the full text.", len(missing), pdfPath, string(missing[:min(len(missing), 10)]), mdPath)
+	}
+	return append(paths, pdfPath), nil
+}
@@ -0,0 +1,75 @@
+package output
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/utkarsh-cpu/videoSummaryGo/media"
+	"github.com/utkarsh-cpu/videoSummaryGo/pipeline"
+	"github.com/utkarsh-cpu/videoSummaryGo/subtitle"
+)
+
+// SubtitleCues builds subtitle cues from the timestamped audio segments of result.
+func SubtitleCues(result *pipeline.VideoResult, opts subtitle.Options) []subtitle.Cue {
+	chunks := make([]subtitle.Chunk, 0, len(result.Chunks))
+	for _, c := range result.Chunks {
+		chunks = append(chunks, subtitle.Chunk{Start: c.Start, End: c.End, Segments: c.AudioSegments})
+	}
+	return subtitle.BuildCues(chunks, opts)
+}
+
+// WriteSubtitles writes <base>.<format> in dir for each format and returns the paths written.
+func WriteSubtitles(result *pipeline.VideoResult, dir string, formats []subtitle.Format, opts subtitle.Options) ([]string, error) {
+	cues := SubtitleCues(result, opts)
+	var paths []string
+	for _, format := range formats {
+		path := filepath.Join(dir, result.BaseName+"."+string(format))
+		f, err := os.Create(path)
+		if err != nil {
+			return paths, fmt.Errorf("error creating subtitle file %s: %w", path, err)
+		}
+		err = subtitle.Write(f, format, cues)
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+		if err != nil {
+			return paths, fmt.Errorf("error writing subtitle file %s: %w", path, err)
+		}
+		paths = append(paths, path)
+	}
+	return paths, nil
+}
+
+// EmbedMode selects how subtitles are put back into the source video.
+type EmbedMode string
+
+// Supported embed modes.
+const (
+	EmbedNone    EmbedMode = ""
+	EmbedSoftMKV EmbedMode = "soft-mkv" // soft subtitle track in a .mkv copy
+	EmbedSoftMP4 EmbedMode = "soft-mp4" // soft mov_text track in a .mp4 copy
+	EmbedBurn    EmbedMode = "burn"     // subtitles rendered into the picture, same container as the input
+)
+
+// EmbedSubtitles writes a copy of the source video with subtitlePath embedded to
+// <base>.subtitled.<ext> in dir and returns its path.
+func EmbedSubtitles(ctx context.Context, result *pipeline.VideoResult, dir string, subtitlePath string, mode EmbedMode) (string, error) {
+	outBase := filepath.Join(dir, result.BaseName+".subtitled")
+	switch mode {
+	case EmbedNone:
+		return "", nil
+	case EmbedSoftMKV:
+		outPath := outBase + ".mkv"
+		return outPath, media.MuxSubtitles(ctx, result.VideoPath, subtitlePath, outPath, result.Language())
+	case EmbedSoftMP4:
+		outPath := outBase + ".mp4"
+		return outPath, media.MuxSubtitles(ctx, result.VideoPath, subtitlePath, outPath, result.Language())
+	case EmbedBurn:
+		outPath := outBase + filepath.Ext(result.VideoPath)
+		return outPath, media.BurnSubtitles(ctx, result.VideoPath, subtitlePath, outPath)
+	default:
+		return "", fmt.Errorf("unknown subtitle embed mode %q", mode)
+	}
+}
@@ -0,0 +1,100 @@
+package output
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/utkarsh-cpu/videoSummaryGo/pipeline"
+)
+
+// completeMarker is appended to every output file once its video is done.
+const completeMarker = "\n--- VIDEO %d PROCESSING COMPLETE ---\n\n"
+
+// TranscriptWriter streams chunk transcripts of one video to <base>_audio_output.txt and
+// <base>_video_output.txt. It is meant to be driven from Pipeline.OnChunk, which delivers
+// chunks in order from a single goroutine.
+type TranscriptWriter struct {
+	videoIndex int
+	audioFile  *os.File
+	videoFile  *os.File
+}
+
+// NewTranscriptWriter creates the transcript files for video in dir.
+func NewTranscriptWriter(video *pipeline.VideoResult, dir string) (*TranscriptWriter, error) {
+	audioFile, err := os.Create(filepath.Join(dir, video.BaseName+"_audio_output.txt"))
+	if err != nil {
+		return nil, fmt.Errorf("error creating audio output file for video %s: %w", video.VideoPath, err)
+	}
+	videoFile, err := os.Create(filepath.Join(dir, video.BaseName+"_video_output.txt"))
+	if err != nil {
+		audioFile.Close()
+		return nil, fmt.Errorf("error creating video output file for video %s: %w", video.VideoPath, err)
+	}
+	return &TranscriptWriter{videoIndex: video.VideoIndex, audioFile: audioFile, videoFile: videoFile}, nil
+}
+
+// WriteChunk appends one chunk's audio and visual transcripts, skipping the ones that failed.
+func (w *TranscriptWriter) WriteChunk(chunk pipeline.ChunkResult) error {
+	if chunk.AudioErr == nil {
+		if _, err := w.audioFile.WriteString(chunk.AudioEntry(w.videoIndex)); err != nil {
+			return fmt.Errorf("error writing to audio file for video %d chunk %d: %w", w.videoIndex, chunk.ChunkNum, err)
+		}
+	}
+	if chunk.VideoErr == nil {
+		if _, err := w.videoFile.WriteString(chunk.VideoEntry(w.videoIndex)); err != nil {
+			return fmt.Errorf("error writing to video file for video %d chunk %d: %w", w.videoIndex, chunk.ChunkNum, err)
+		}
+	}
+	return nil
+}
+
+// Close appends the completion marker and closes both files.
+func (w *TranscriptWriter) Close() error {
+	var firstErr error
+	for _, f := range []*os.File{w.audioFile, w.videoFile} {
+		if _, err := fmt.Fprintf(f, completeMarker, w.videoIndex); err != nil && firstErr == nil {
+			firstErr = err
+		}
+		if err := f.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// WriteSummary writes the summary of result to <base>_output.txt in dir.
+func WriteSummary(result *pipeline.VideoResult, dir string) error {
+	return writeFile(filepath.Join(dir, result.BaseName+"_output.txt"), result.Summary, result.VideoIndex)
+}
+
+// WriteText writes the summary, audio and video transcripts of a finished result to
+// <base>_output.txt, <base>_audio_output.txt and <base>_video_output.txt in dir.
+func WriteText(result *pipeline.VideoResult, dir string) error {
+	if err := WriteSummary(result, dir); err != nil {
+		return err
+	}
+	if err := WriteAudioTranscript(result, dir); err != nil {
+		return err
+	}
+	return WriteVideoTranscript(result, dir)
+}
+
+// WriteAudioTranscript writes the audio transcript of result to <base>_audio_output.txt in dir.
+func WriteAudioTranscript(result *pipeline.VideoResult, dir string) error {
+	return writeFile(filepath.Join(dir, result.BaseName+"_audio_output.txt"), result.AudioTranscript(), result.VideoIndex)
+}
+
+// WriteVideoTranscript writes the visual transcript of result to <base>_video_output.txt in dir.
+func WriteVideoTranscript(result *pipeline.VideoResult, dir string) error {
+	return writeFile(filepath.Join(dir, result.BaseName+"_video_output.txt"), result.VideoTranscript(), result.VideoIndex)
+}
+
+// writeFile writes content followed by the completion marker to path.
+func writeFile(path string, content string, videoIndex int) error {
+	content += fmt.Sprintf(completeMarker, videoIndex)
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		return fmt.Errorf("error writing output file %s: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+package pipeline
+
+// indexedChunk is a chunk result tagged with its position in the video.
+type indexedChunk struct {
+	index  int
+	result ChunkResult
+}
+
+// assembler collects chunk results that finish in any order into a chunk-indexed slice.
+// A single goroutine (run) owns the slice and emits each result in chunk order as soon as
+// every earlier chunk has arrived, so consumers never see interleaved or reordered chunks.
+type assembler struct {
+	in       chan indexedChunk
+	done     chan struct{}
+	chunks   []ChunkResult
+	received []bool
+	emit     func(ChunkResult)
+	arrive   func(ChunkResult)
+}
+
+// newAssembler starts an assembler for n chunks. emit is called in chunk order; arrive is called
+// for every chunk in arrival order, also from the writer goroutine. Either may be nil.
+func newAssembler(n int, emit func(ChunkResult), arrive func(ChunkResult)) *assembler {
+	a := &assembler{
+		in:       make(chan indexedChunk),
+		done:     make(chan struct{}),
+		chunks:   make([]ChunkResult, n),
+		received: make([]bool, n),
+		emit:     emit,
+		arrive:   arrive,
+	}
+	go a.run()
+	return a
+}
+
+// add hands a finished chunk to the writer goroutine. It is safe for concurrent use.
+func (a *assembler) add(index int, result ChunkResult) {
+	a.in <- indexedChunk{index: index, result: result}
+}
+
+// wait closes the input and returns the results in chunk order once the writer has drained it.
+// Chunks that were never added (because the run was cancelled) are left out.
+func (a *assembler) wait() []ChunkResult {
+	close(a.in)
+	<-a.done
+	chunks := a.chunks[:0]
+	for i, c := range a.chunks {
+		if a.received[i] {
+			chunks = append(chunks, c)
+		}
+	}
+	return chunks
+}
+
+// run stores incoming results and emits the contiguous prefix that is complete.
+func (a *assembler) run() {
+	defer close(a.done)
+	next := 0
+	for c := range a.in {
+		a.chunks[c.index] = c.result
+		a.received[c.index] = true
+		if a.arrive != nil {
+			a.arrive(c.result)
+		}
+		for next < len(a.chunks) && a.received[next] {
+			if a.emit != nil {
+				a.emit(a.chunks[next])
+			}
+			next++
+		}
+	}
+}
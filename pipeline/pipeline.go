@@ -0,0 +1,555 @@
+package pipeline
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/utkarsh-cpu/videoSummaryGo/audio"
+	"github.com/utkarsh-cpu/videoSummaryGo/cache"
+	"github.com/utkarsh-cpu/videoSummaryGo/checkpoint"
+	"github.com/utkarsh-cpu/videoSummaryGo/failure"
+	"github.com/utkarsh-cpu/videoSummaryGo/llm"
+	"github.com/utkarsh-cpu/videoSummaryGo/media"
+	"github.com/utkarsh-cpu/videoSummaryGo/progress"
+	"github.com/utkarsh-cpu/videoSummaryGo/retry"
+	"github.com/utkarsh-cpu/videoSummaryGo/summarize"
+	"github.com/utkarsh-cpu/videoSummaryGo/visual"
+)
+
+// Config holds the settings for a Pipeline.
+type Config struct {
+	LLMBackend       string // "gemini" (default), "openai" or "ollama"
+	LLM              string // model name
+	APIKey           string
+	LLMBaseURL       string           // server URL for HTTP backends, e.g. http://localhost:8080/v1
+	MediaPolling     llm.MediaPolling // wait for uploaded chunks to become active
+	Retry            retry.Policy     // retries of LLM calls and uploads
+	ChunkDuration    int              // seconds
+	SilenceWindow    time.Duration    // move each cut to a pause within this distance (0 cuts at fixed intervals)
+	WhisperCLIPath   string
+	WhisperModelPath string
+	WhisperThreads   int
+	WhisperLanguage  string
+	OCRLanguage      string // tesseract language packs, e.g. "eng+deu"
+
+	// Logger receives progress messages from the pipeline, its transcribers and the LLM client
+	// it creates; nil discards them.
+	Logger progress.Logger
+
+	// SummaryPrompt replaces summarize.DefaultInstructions, and VisualPrompt the instruction
+	// the default LLM visual transcribers send with each chunk.
+	SummaryPrompt string
+	VisualPrompt  string
+
+	// ChunkWorkers is the number of chunks processed at once (default DefaultChunkWorkers).
+	ChunkWorkers int
+	// WhisperConcurrency limits concurrent audio transcriptions, which are CPU-bound
+	// (default NumCPU / WhisperThreads, at least 1).
+	WhisperConcurrency int
+	// VisualConcurrency limits concurrent visual transcriptions, which upload to and
+	// prompt the rate-limited LLM (default DefaultVisualConcurrency).
+	VisualConcurrency int
+
+	// ManifestPath, when set, returns where to keep the checkpoint manifest of a video ("" to
+	// disable it for that video). Progress is recorded after every chunk.
+	ManifestPath func(videoPath string) string
+	// Resume reuses completed chunks and videos from existing manifests whose source file,
+	// chunking, models and prompts are unchanged.
+	Resume bool
+
+	// SkipAudio, SkipVisual and SkipSummary leave out a stage. The LLM is only connected when the
+	// summary or the default visual transcribers need it.
+	SkipAudio   bool
+	SkipVisual  bool
+	SkipSummary bool
+
+	// Cache, when set, serves audio and visual transcriptions and LLM responses from a local
+	// content-addressed cache and stores new ones in it.
+	Cache *cache.Store
+
+	// LLMClient overrides the client built from the LLM* fields. The pipeline does not close it
+	// and does not add retries; wrap it with llm.WithRetry for that.
+	LLMClient llm.LLM
+	// AudioTranscriber overrides the default whisper-cli transcriber built from the Whisper* fields.
+	AudioTranscriber audio.AudioTranscriber
+	// VisualTranscriber overrides the default fallback chain: LLM video upload (Gemini) or
+	// LLM frames (other backends), then Tesseract frames.
+	VisualTranscriber visual.VisualTranscriber
+}
+
+// Concurrency defaults used when the corresponding Config field is zero.
+const (
+	DefaultChunkWorkers      = 4
+	DefaultVisualConcurrency = 2
+)
+
+// ChunkResult is the outcome of transcribing one chunk.
+type ChunkResult struct {
+	ChunkNum int
+	// Start and End are the chunk's position in the source video.
+	Start time.Duration
+	End   time.Duration
+	// AudioTranscript lists AudioSegments with absolute video timestamps, or holds the
+	// backend's raw text when it returned no segments.
+	AudioTranscript string
+	AudioSegments   []audio.Segment // timestamps are absolute within the source video
+	AudioLanguage   string
+	VideoTranscript string
+	VideoBackend    string // name of the visual transcriber that produced VideoTranscript
+	AudioErr        error
+	VideoErr        error
+	// AudioElapsed and VideoElapsed are the wall-clock times spent transcribing.
+	AudioElapsed time.Duration
+	VideoElapsed time.Duration
+}
+
+// VideoResult is the outcome of processing one video.
+type VideoResult struct {
+	VideoIndex int // 1-based
+	VideoPath  string
+	BaseName   string
+	Probe      *media.ProbeInfo // nil if ffprobe failed
+	Chunks     []ChunkResult
+	Summary    string
+	// Failures lists every error of the video with the stage and chunk it happened in.
+	Failures []failure.Failure
+	Timings  Timings
+}
+
+// Stages reported in failure.Failure.Stage.
+const (
+	StageProbe       = "probe"
+	StageChunking    = "chunking"
+	StageAudio       = "audio"
+	StageVisual      = "visual"
+	StageSummary     = "summary"
+	StageInterrupted = "interrupted" // the run was stopped before the video was finished
+)
+
+// Timings records when a video was processed and how long each stage took.
+type Timings struct {
+	Started  time.Time
+	Chunking time.Duration
+	Chunks   time.Duration // transcription of all chunks
+	Summary  time.Duration
+	Total    time.Duration
+}
+
+// AudioEntry formats the chunk's audio transcript as one entry of the combined transcript.
+func (c ChunkResult) AudioEntry(videoIndex int) string {
+	return fmt.Sprintf("Video Index: %d, Chunk: %d\n%s\n", videoIndex, c.ChunkNum, c.AudioTranscript)
+}
+
+// VideoEntry formats the chunk's visual transcript as one entry of the combined transcript.
+func (c ChunkResult) VideoEntry(videoIndex int) string {
+	return fmt.Sprintf("Video Index: %d, Chunk: %d\n%s\n", videoIndex, c.ChunkNum, c.VideoTranscript)
+}
+
+// Language returns the first audio language reported by the transcriber, or "".
+func (r *VideoResult) Language() string {
+	for _, c := range r.Chunks {
+		if c.AudioLanguage != "" {
+			return c.AudioLanguage
+		}
+	}
+	return ""
+}
+
+// Failed reports whether any stage of the video failed.
+func (r *VideoResult) Failed() bool {
+	return len(r.Failures) > 0
+}
+
+// AudioTranscript returns the audio transcripts of all chunks in chunk order. Chunks whose audio
+// transcription failed are left out.
+func (r *VideoResult) AudioTranscript() string {
+	var b strings.Builder
+	for _, c := range r.Chunks {
+		if c.AudioErr == nil {
+			b.WriteString(c.AudioEntry(r.VideoIndex))
+		}
+	}
+	return b.String()
+}
+
+// VideoTranscript returns the visual transcripts of all chunks in chunk order. Chunks whose visual
+// transcription failed are left out.
+func (r *VideoResult) VideoTranscript() string {
+	var b strings.Builder
+	for _, c := range r.Chunks {
+		if c.VideoErr == nil {
+			b.WriteString(c.VideoEntry(r.VideoIndex))
+		}
+	}
+	return b.String()
+}
+
+// Pipeline chunks, transcribes and summarizes videos.
+type Pipeline struct {
+	cfg     Config
+	llm     llm.LLM
+	ownsLLM bool
+	audio   audio.AudioTranscriber
+	visual  visual.VisualTranscriber
+
+	workers      int
+	whisperGuard chan struct{} // semaphore for audio transcriptions
+	visualGuard  chan struct{} // semaphore for visual transcriptions
+
+	// OnChunk, when set, is called from a single goroutine with each chunk of video in chunk
+	// order, as soon as that chunk and every earlier one are finished.
+	OnChunk func(video *VideoResult, chunk ChunkResult)
+	// OnVideo, when set, is called with each video's result as soon as it is finished.
+	OnVideo func(*VideoResult)
+}
+
+// New creates a Pipeline and, unless no enabled stage needs it, connects to the LLM API.
+func New(ctx context.Context, cfg Config) (*Pipeline, error) {
+	if cfg.SilenceWindow < 0 || (cfg.SilenceWindow > 0 && cfg.SilenceWindow >= time.Duration(cfg.ChunkDuration)*time.Second) {
+		return nil, fmt.Errorf("invalid silence window %s: must be shorter than the chunk duration", cfg.SilenceWindow)
+	}
+	var client llm.LLM
+	ownsLLM := false
+	if !cfg.SkipSummary || (!cfg.SkipVisual && cfg.VisualTranscriber == nil) {
+		client = cfg.LLMClient
+		if client == nil {
+			var err error
+			client, err = llm.New(ctx, llm.Options{Backend: cfg.LLMBackend, Model: cfg.LLM, APIKey: cfg.APIKey, BaseURL: cfg.LLMBaseURL, MediaPolling: cfg.MediaPolling, Retry: cfg.Retry, Logger: cfg.Logger})
+			if err != nil {
+				return nil, err
+			}
+			ownsLLM = true
+		}
+		if cfg.Cache != nil {
+			client = &cache.LLM{LLM: client, Store: cfg.Cache}
+		}
+	}
+	audioTranscriber := cfg.AudioTranscriber
+	if audioTranscriber == nil && !cfg.SkipAudio {
+		audioTranscriber = &audio.WhisperCLITranscriber{
+			CLIPath:   cfg.WhisperCLIPath,
+			ModelPath: cfg.WhisperModelPath,
+			Threads:   cfg.WhisperThreads,
+			Language:  cfg.WhisperLanguage,
+		}
+	}
+	if cfg.SkipAudio {
+		audioTranscriber = nil
+	}
+	visualTranscriber := cfg.VisualTranscriber
+	if cfg.SkipVisual {
+		visualTranscriber = nil
+	} else if visualTranscriber == nil {
+		var primary visual.VisualTranscriber = &visual.LLMVideoTranscriber{LLM: client, Prompt: cfg.VisualPrompt}
+		if client.Name() != "gemini" {
+			primary = &visual.LLMFramesTranscriber{LLM: client, Prompt: cfg.VisualPrompt}
+		}
+		visualTranscriber = visual.NewFallbackChain(primary, &visual.TesseractFramesTranscriber{Language: cfg.OCRLanguage})
+	}
+	if cfg.Cache != nil && audioTranscriber != nil {
+		audioTranscriber = &cache.AudioTranscriber{
+			Inner:    audioTranscriber,
+			Store:    cfg.Cache,
+			Identity: fmt.Sprintf("%T|%s|%s", audioTranscriber, cfg.WhisperModelPath, cfg.WhisperLanguage),
+		}
+	}
+	if cfg.Cache != nil && visualTranscriber != nil {
+		identity := ""
+		if client != nil {
+			identity = client.Name() + "|" + client.Model()
+		}
+		// Only non-default settings are added, so entries stored with the defaults stay valid.
+		if cfg.VisualPrompt != "" || cfg.OCRLanguage != "" {
+			identity += "|" + cfg.VisualPrompt + "|" + cfg.OCRLanguage
+		}
+		visualTranscriber = cacheVisual(visualTranscriber, cfg.Cache, identity)
+	}
+	workers := cfg.ChunkWorkers
+	if workers <= 0 {
+		workers = DefaultChunkWorkers
+	}
+	whisperConcurrency := cfg.WhisperConcurrency
+	if whisperConcurrency <= 0 {
+		whisperConcurrency = max(1, runtime.NumCPU()/max(1, cfg.WhisperThreads))
+	}
+	visualConcurrency := cfg.VisualConcurrency
+	if visualConcurrency <= 0 {
+		visualConcurrency = DefaultVisualConcurrency
+	}
+	return &Pipeline{
+		cfg:          cfg,
+		llm:          client,
+		ownsLLM:      ownsLLM,
+		audio:        audioTranscriber,
+		visual:       visualTranscriber,
+		workers:      workers,
+		whisperGuard: make(chan struct{}, whisperConcurrency),
+		visualGuard:  make(chan struct{}, visualConcurrency),
+	}, nil
+}
+
+// cacheVisual wraps t to serve its results from store. The members of a fallback chain are wrapped
+// one by one, so that a fallback result stored after the primary failed once is never served in
+// place of the primary's.
+func cacheVisual(t visual.VisualTranscriber, store *cache.Store, identity string) visual.VisualTranscriber {
+	if chain, ok := t.(*visual.FallbackChain); ok {
+		members := make([]visual.VisualTranscriber, len(chain.Transcribers))
+		for i, member := range chain.Transcribers {
+			members[i] = cacheVisual(member, store, identity)
+		}
+		return visual.NewFallbackChain(members...)
+	}
+	return &cache.VisualTranscriber{Inner: t, Store: store, Identity: identity}
+}
+
+// Close releases the LLM client if the pipeline created one.
+func (p *Pipeline) Close() error {
+	if !p.ownsLLM {
+		return nil
+	}
+	return p.llm.Close()
+}
+
+// Run processes input, a video file or a folder of videos, and returns one result per video.
+func (p *Pipeline) Run(ctx context.Context, input string) ([]*VideoResult, error) {
+	ctx = progress.NewContext(ctx, p.cfg.Logger)
+	videoPaths, err := media.FindVideos(input)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(videoPaths) == 0 {
+		progress.Printf(ctx, "No video files found to process.")
+		return nil, nil
+	}
+	progress.Printf(ctx, "Found %d video files in %s.", len(videoPaths), input)
+
+	var results []*VideoResult
+	for videoIndex, videoPath := range videoPaths {
+		if ctx.Err() != nil {
+			progress.Printf(ctx, "Interrupted: skipping the remaining %d videos.", len(videoPaths)-videoIndex)
+			return results, ctx.Err()
+		}
+		result := p.RunVideo(ctx, videoIndex+1, videoPath)
+		results = append(results, result)
+		if p.OnVideo != nil {
+			p.OnVideo(result)
+		}
+	}
+
+	progress.Printf(ctx, "\nAll videos processing complete.")
+	return results, nil
+}
+
+// RunVideo processes a single video. videoIndex is 1-based and only used for naming and logs.
+func (p *Pipeline) RunVideo(ctx context.Context, videoIndex int, videoPath string) *VideoResult {
+	ctx = progress.NewContext(ctx, p.cfg.Logger)
+	result := &VideoResult{VideoIndex: videoIndex, VideoPath: videoPath, BaseName: media.BaseName(videoPath)}
+	result.Timings.Started = time.Now()
+	defer func() { result.Timings.Total = time.Since(result.Timings.Started) }()
+	progress.Printf(ctx, "\n--- START PROCESSING VIDEO %d: %s ---", videoIndex, videoPath)
+
+	failures := &failure.Collector{}
+	defer func() { result.Failures = failures.Failures() }()
+
+	probe, err := media.Probe(ctx, videoPath)
+	failures.Add(StageProbe, -1, err)
+	result.Probe = probe
+
+	manifest, manifestPath := p.openManifest(ctx, videoPath)
+	if manifest != nil && manifest.Complete {
+		progress.Printf(ctx, "Video %d already completed, skipping.", videoIndex)
+		for i := 0; i < len(manifest.Chunks); i++ {
+			if entry, ok := manifest.Done(i); ok {
+				result.Chunks = append(result.Chunks, restoreChunk(entry))
+			}
+		}
+		result.Summary = manifest.Summary
+		return result
+	}
+
+	progress.Printf(ctx, "Chunking video sequentially...")
+	stageStart := time.Now()
+	chunks, err := media.ChunkVideo(ctx, videoPath, p.cfg.ChunkDuration, p.cfg.SilenceWindow, videoIndex, result.BaseName)
+	result.Timings.Chunking = time.Since(stageStart)
+	if err != nil {
+		failures.Add(StageChunking, -1, fmt.Errorf("error chunking video %s: %w", videoPath, err))
+		return result
+	}
+	if len(chunks) > 0 {
+		defer os.RemoveAll(chunks[0].TempDir)
+	}
+	progress.Printf(ctx, "Video chunking complete.")
+
+	progress.Printf(ctx, "Processing %d video chunks with %d workers...", len(chunks), p.workers)
+	stageStart = time.Now()
+	result.Chunks = p.processChunks(ctx, result, chunks, manifest, manifestPath, failures)
+	result.Timings.Chunks = time.Since(stageStart)
+
+	if err := ctx.Err(); err != nil {
+		// Record what finished so a resumed run only redoes the rest; the summary needs every chunk.
+		failures.Add(StageInterrupted, -1, &failure.Error{Kind: failure.Interrupted, Err: fmt.Errorf("video %d interrupted after %d of %d chunks: %w", videoIndex, len(result.Chunks), len(chunks), err)})
+		if manifest != nil {
+			saveManifest(ctx, manifest, manifestPath)
+		}
+		progress.Printf(ctx, "\n--- INTERRUPTED PROCESSING VIDEO %d: %s ---", videoIndex, videoPath)
+		return result
+	}
+
+	if !p.cfg.SkipSummary {
+		progress.Printf(ctx, "All video chunks processed. Sending combined prompt to LLM...")
+		stageStart = time.Now()
+		result.Summary, err = summarize.Summarize(ctx, p.llm, p.cfg.SummaryPrompt, result.AudioTranscript(), result.VideoTranscript(), videoIndex)
+		result.Timings.Summary = time.Since(stageStart)
+		if err == nil && result.Summary == "" {
+			err = failure.Wrap(failure.LLM, errors.New("LLM returned no summary"))
+		}
+		if err != nil {
+			failures.Add(StageSummary, -1, fmt.Errorf("error summarizing video %d: %w", videoIndex, err))
+		}
+	}
+	if manifest != nil {
+		manifest.SummaryDone = result.Summary != ""
+		manifest.Summary = result.Summary
+		manifest.Complete = (manifest.SummaryDone || p.cfg.SkipSummary) && len(failures.Failures()) == 0
+		saveManifest(ctx, manifest, manifestPath)
+	}
+	progress.Printf(ctx, "\n--- FINISHED PROCESSING VIDEO %d: %s ---", videoIndex, videoPath)
+	return result
+}
+
+// processChunks runs processChunk over chunks with at most p.workers in flight and returns
+// the results in chunk order. Finished chunks are passed to OnChunk in order as they complete.
+// Chunks already completed in manifest are reused; every new result is recorded in it.
+// Chunk failures are added to failures as soon as they happen.
+func (p *Pipeline) processChunks(ctx context.Context, video *VideoResult, chunks []media.ChunkData, manifest *checkpoint.Manifest, manifestPath string, failures *failure.Collector) []ChunkResult {
+	var emit, arrive func(ChunkResult)
+	if p.OnChunk != nil {
+		emit = func(c ChunkResult) { p.OnChunk(video, c) }
+	}
+	// The writer goroutine updates manifest while chunks are dispatched, so dispatching reads a copy.
+	var done map[int]*checkpoint.Chunk
+	if manifest != nil {
+		done = manifest.Completed()
+		arrive = func(c ChunkResult) {
+			manifest.Chunks[c.ChunkNum] = checkpointChunk(c)
+			saveManifest(ctx, manifest, manifestPath)
+		}
+	}
+	asm := newAssembler(len(chunks), emit, arrive)
+	var wg sync.WaitGroup
+	guard := make(chan struct{}, p.workers) // Semaphore
+
+	for i, chunkData := range chunks {
+		if entry, ok := done[chunkData.ChunkNum]; ok {
+			progress.Printf(ctx, "Chunk %d for video %d: reusing checkpointed result.", chunkData.ChunkNum, chunkData.VideoIndex)
+			os.Remove(chunkData.AudioPath)
+			os.Remove(chunkData.VideoPath)
+			asm.add(i, restoreChunk(entry))
+			continue
+		}
+		select {
+		case guard <- struct{}{}: // Acquire a slot
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			progress.Printf(ctx, "Interrupted: not starting the remaining chunks of video %d.", chunkData.VideoIndex)
+			break
+		}
+		wg.Add(1)
+
+		go func(i int, chunk media.ChunkData) {
+			defer wg.Done()
+			defer func() { <-guard }() // Release the slot
+			asm.add(i, p.processChunk(ctx, chunk, failures))
+		}(i, chunkData)
+	}
+
+	wg.Wait()
+	return asm.wait()
+}
+
+// processChunk transcribes the audio and video of one chunk concurrently. Failures are recorded
+// on the result and in failures; a failed transcript is left empty.
+func (p *Pipeline) processChunk(ctx context.Context, chunk media.ChunkData, failures *failure.Collector) ChunkResult {
+	result := ChunkResult{ChunkNum: chunk.ChunkNum, Start: chunk.Start, End: chunk.End}
+	if chunk.Err != nil {
+		result.AudioErr = chunk.Err
+		result.VideoErr = chunk.Err
+		failures.Add(StageChunking, chunk.ChunkNum, chunk.Err)
+		return result
+	}
+
+	progress.Printf(ctx, "Processing chunk %d for video %d...", chunk.ChunkNum, chunk.VideoIndex)
+	defer progress.Printf(ctx, "Finished processing chunk %d for video %d.", chunk.ChunkNum, chunk.VideoIndex)
+
+	var wg sync.WaitGroup
+	wg.Add(2) // We have two goroutines: audio and video transcription
+
+	go func() {
+		defer wg.Done()
+		if p.audio == nil {
+			os.Remove(chunk.AudioPath)
+			return
+		}
+		p.whisperGuard <- struct{}{}
+		started := time.Now()
+		transcript, err := p.audio.Transcribe(ctx, chunk.AudioPath, audio.Options{VideoIndex: chunk.VideoIndex, ChunkNum: chunk.ChunkNum})
+		result.AudioElapsed = time.Since(started)
+		<-p.whisperGuard
+		if err != nil {
+			result.AudioErr = fmt.Errorf("error transcribing audio for video %d chunk %d: %w", chunk.VideoIndex, chunk.ChunkNum, interrupted(ctx, err))
+			failures.Add(StageAudio, chunk.ChunkNum, result.AudioErr)
+		} else if len(transcript.Segments) > 0 {
+			result.AudioLanguage = transcript.Language
+			result.AudioSegments = audio.Offset(transcript.Segments, chunk.Start)
+			result.AudioTranscript = audio.FormatSegments(result.AudioSegments)
+		} else {
+			result.AudioTranscript = transcript.Text
+		}
+		if err == nil {
+			progress.Printf(ctx, "Chunk %d for video %d: Audio transcribed.", chunk.ChunkNum, chunk.VideoIndex)
+		}
+		os.Remove(chunk.AudioPath) // Delete audio chunk
+	}()
+
+	go func() {
+		defer wg.Done()
+		if p.visual == nil {
+			os.Remove(chunk.VideoPath)
+			return
+		}
+		p.visualGuard <- struct{}{}
+		started := time.Now()
+		transcript, err := p.visual.Transcribe(ctx, chunk.VideoPath, visual.Options{VideoIndex: chunk.VideoIndex, ChunkNum: chunk.ChunkNum})
+		result.VideoElapsed = time.Since(started)
+		<-p.visualGuard
+		if err != nil {
+			result.VideoErr = fmt.Errorf("error transcribing video for video %d chunk %d: %w", chunk.VideoIndex, chunk.ChunkNum, interrupted(ctx, err))
+			failures.Add(StageVisual, chunk.ChunkNum, result.VideoErr)
+		} else {
+			result.VideoTranscript = transcript.Text
+			result.VideoBackend = transcript.Backend
+			progress.Printf(ctx, "Chunk %d for video %d: Video transcribed by %s.", chunk.ChunkNum, chunk.VideoIndex, result.VideoBackend)
+		}
+		os.Remove(chunk.VideoPath) // Delete video chunk
+	}()
+
+	wg.Wait() // Wait for both goroutines to complete
+	return result
+}
+
+// interrupted tags err as failure.Interrupted when ctx was cancelled, since killed tools report
+// their exit status rather than the cancellation.
+func interrupted(ctx context.Context, err error) error {
+	if ctx.Err() != nil {
+		return &failure.Error{Kind: failure.Interrupted, Err: err}
+	}
+	return err
+}
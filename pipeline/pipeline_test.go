@@ -0,0 +1,212 @@
+package pipeline
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/utkarsh-cpu/videoSummaryGo/audio"
+	"github.com/utkarsh-cpu/videoSummaryGo/cache"
+	"github.com/utkarsh-cpu/videoSummaryGo/checkpoint"
+	"github.com/utkarsh-cpu/videoSummaryGo/failure"
+	"github.com/utkarsh-cpu/videoSummaryGo/llm"
+	"github.com/utkarsh-cpu/videoSummaryGo/media"
+	"github.com/utkarsh-cpu/videoSummaryGo/visual"
+)
+
+type fakeAudio struct{}
+
+func (fakeAudio) Transcribe(ctx context.Context, audioPath string, opts audio.Options) (*audio.Transcript, error) {
+	return &audio.Transcript{Text: fmt.Sprintf("audio %d", opts.ChunkNum)}, nil
+}
+
+type fakeVisual struct{}
+
+func (fakeVisual) Name() string { return "fake" }
+
+func (fakeVisual) Transcribe(ctx context.Context, videoPath string, opts visual.Options) (*visual.Result, error) {
+	return &visual.Result{Text: fmt.Sprintf("video %d", opts.ChunkNum), Backend: "fake"}, nil
+}
+
+// TestProcessChunksResume resumes from a manifest with every other chunk completed while the
+// others finish and are recorded concurrently; run it with -race.
+func TestProcessChunksResume(t *testing.T) {
+	p, err := New(context.Background(), Config{SkipSummary: true, AudioTranscriber: fakeAudio{}, VisualTranscriber: fakeVisual{}, ChunkWorkers: 4})
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	const n = 40
+	manifest := checkpoint.New(checkpoint.Fingerprint{})
+	var chunks []media.ChunkData
+	for i := 0; i < n; i++ {
+		start := time.Duration(i) * time.Minute
+		chunks = append(chunks, media.ChunkData{
+			ChunkNum:  i,
+			AudioPath: filepath.Join(dir, fmt.Sprintf("chunk_%d.wav", i)),
+			VideoPath: filepath.Join(dir, fmt.Sprintf("chunk_%d.mp4", i)),
+			Start:     start,
+			End:       start + time.Minute,
+		})
+		if i%2 == 0 {
+			manifest.Chunks[i] = &checkpoint.Chunk{Index: i, Status: checkpoint.ChunkDone, Start: start, End: start + time.Minute, AudioTranscript: "restored", VideoTranscript: "restored"}
+		}
+	}
+
+	video := &VideoResult{VideoIndex: 1}
+	var failures failure.Collector
+	results := p.processChunks(context.Background(), video, chunks, manifest, filepath.Join(dir, "manifest.json"), &failures)
+
+	if len(results) != n {
+		t.Fatalf("got %d results, want %d", len(results), n)
+	}
+	for i, r := range results {
+		want := fmt.Sprintf("audio %d", i)
+		if i%2 == 0 {
+			want = "restored"
+		}
+		if r.ChunkNum != i || r.AudioTranscript != want {
+			t.Errorf("result %d: chunk %d with audio %q, want chunk %d with %q", i, r.ChunkNum, r.AudioTranscript, i, want)
+		}
+	}
+	if got := len(manifest.Completed()); got != n {
+		t.Errorf("manifest has %d completed chunks, want %d", got, n)
+	}
+	saved, err := checkpoint.Load(filepath.Join(dir, "manifest.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := len(saved.Completed()); got != n {
+		t.Errorf("saved manifest has %d completed chunks, want %d", got, n)
+	}
+}
+
+func TestNewRejectsSilenceWindow(t *testing.T) {
+	for _, window := range []time.Duration{-time.Second, time.Minute, 2 * time.Minute} {
+		cfg := Config{ChunkDuration: 60, SilenceWindow: window, SkipSummary: true, AudioTranscriber: fakeAudio{}, VisualTranscriber: fakeVisual{}}
+		if _, err := New(context.Background(), cfg); err == nil {
+			t.Errorf("New with a %s silence window for 60s chunks succeeded, want an error", window)
+		}
+	}
+	cfg := Config{ChunkDuration: 60, SilenceWindow: 5 * time.Second, SkipSummary: true, AudioTranscriber: fakeAudio{}, VisualTranscriber: fakeVisual{}}
+	if _, err := New(context.Background(), cfg); err != nil {
+		t.Errorf("New with a 5s silence window: %v", err)
+	}
+}
+
+// recorder is a progress.Logger that keeps the messages.
+type recorder struct {
+	mu       sync.Mutex
+	messages []string
+}
+
+func (r *recorder) Printf(format string, v ...any) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.messages = append(r.messages, fmt.Sprintf(format, v...))
+}
+
+func TestRunReportsToLogger(t *testing.T) {
+	var log recorder
+	p, err := New(context.Background(), Config{SkipSummary: true, AudioTranscriber: fakeAudio{}, VisualTranscriber: fakeVisual{}, Logger: &log})
+	if err != nil {
+		t.Fatal(err)
+	}
+	results, err := p.Run(context.Background(), t.TempDir())
+	if err != nil || len(results) != 0 {
+		t.Fatalf("Run of an empty folder = %d results, %v", len(results), err)
+	}
+	if want := []string{"No video files found to process."}; !slices.Equal(log.messages, want) {
+		t.Errorf("messages = %q, want %q", log.messages, want)
+	}
+}
+
+// flakyVisual fails its first call.
+type flakyVisual struct{ calls int }
+
+func (*flakyVisual) Name() string { return "llm-video" }
+
+func (v *flakyVisual) Transcribe(ctx context.Context, videoPath string, opts visual.Options) (*visual.Result, error) {
+	v.calls++
+	if v.calls == 1 {
+		return nil, errors.New("503 service unavailable")
+	}
+	return &visual.Result{Text: "from the LLM"}, nil
+}
+
+// TestCachedFallbackNotServedForPrimary checks that a fallback result cached while the primary
+// transcriber was failing does not replace the primary's result on the next run.
+func TestCachedFallbackNotServedForPrimary(t *testing.T) {
+	store, err := cache.NewStore(t.TempDir(), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	primary := &flakyVisual{}
+	chain := visual.NewFallbackChain(primary, fakeVisual{})
+	p, err := New(context.Background(), Config{SkipAudio: true, SkipSummary: true, VisualTranscriber: chain, Cache: store})
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "chunk_0.mp4")
+	var backends []string
+	for range 3 {
+		if err := os.WriteFile(path, []byte("video bytes"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		result, err := p.visual.Transcribe(context.Background(), path, visual.Options{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		backends = append(backends, result.Backend)
+	}
+	if want := []string{"fake", "llm-video", "llm-video"}; !slices.Equal(backends, want) {
+		t.Errorf("backends = %q, want %q", backends, want)
+	}
+	if primary.calls != 2 {
+		t.Errorf("primary called %d times, want 2", primary.calls)
+	}
+}
+
+// fakeLLM names a model; the pipeline under test never calls it.
+type fakeLLM struct{ llm.LLM }
+
+func (fakeLLM) Name() string  { return "fake" }
+func (fakeLLM) Model() string { return "model" }
+
+// TestFingerprintSkippedStages checks that a transcribe-only checkpoint is not reused by a full run.
+func TestFingerprintSkippedStages(t *testing.T) {
+	video := filepath.Join(t.TempDir(), "talk.mp4")
+	if err := os.WriteFile(video, []byte("video bytes"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	configs := map[string]Config{
+		"transcribe": {SkipVisual: true, SkipSummary: true, AudioTranscriber: fakeAudio{}},
+		"ocr":        {SkipAudio: true, SkipSummary: true, VisualTranscriber: fakeVisual{}},
+		"no summary": {SkipSummary: true, AudioTranscriber: fakeAudio{}, VisualTranscriber: fakeVisual{}, LLMClient: fakeLLM{}},
+		"run":        {AudioTranscriber: fakeAudio{}, VisualTranscriber: fakeVisual{}, LLMClient: fakeLLM{}},
+	}
+	fingerprints := map[string]checkpoint.Fingerprint{}
+	for name, cfg := range configs {
+		p, err := New(context.Background(), cfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if fingerprints[name], err = p.fingerprint(video); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for written, fp := range fingerprints {
+		m := checkpoint.New(fp)
+		for current, other := range fingerprints {
+			if valid := m.Valid(other); valid != (written == current) {
+				t.Errorf("manifest of %s valid for %s = %v", written, current, valid)
+			}
+		}
+	}
+}
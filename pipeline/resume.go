@@ -0,0 +1,129 @@
+package pipeline
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"os"
+	"path/filepath"
+
+	"github.com/utkarsh-cpu/videoSummaryGo/checkpoint"
+	"github.com/utkarsh-cpu/videoSummaryGo/progress"
+	"github.com/utkarsh-cpu/videoSummaryGo/summarize"
+)
+
+// fingerprint describes the inputs that determine the result for videoPath.
+func (p *Pipeline) fingerprint(videoPath string) (checkpoint.Fingerprint, error) {
+	abs, err := filepath.Abs(videoPath)
+	if err != nil {
+		return checkpoint.Fingerprint{}, err
+	}
+	info, err := os.Stat(videoPath)
+	if err != nil {
+		return checkpoint.Fingerprint{}, err
+	}
+	// The visual prompt is empty by default, which keeps the hash of older manifests.
+	prompt := sha256.Sum256([]byte(summarize.BuildPrompt(p.cfg.SummaryPrompt, "", "") + p.cfg.VisualPrompt))
+	fp := checkpoint.Fingerprint{
+		Source:        abs,
+		Size:          info.Size(),
+		ModTime:       info.ModTime(),
+		ChunkDuration: p.cfg.ChunkDuration,
+		SilenceWindow: p.cfg.SilenceWindow,
+		WhisperModel:  p.cfg.WhisperModelPath,
+		Language:      p.cfg.WhisperLanguage,
+		PromptHash:    hex.EncodeToString(prompt[:]),
+		SkipAudio:     p.audio == nil,
+		SkipVisual:    p.visual == nil,
+		SkipSummary:   p.cfg.SkipSummary,
+	}
+	// Skipped stages leave their fields empty, so a partial run never resumes a full one or vice versa.
+	if p.llm != nil {
+		fp.LLMBackend = p.llm.Name()
+		fp.LLM = p.llm.Model()
+	}
+	if p.audio == nil {
+		fp.WhisperModel = ""
+		fp.Language = ""
+	}
+	if p.visual != nil {
+		fp.Visual = p.visual.Name()
+		fp.OCRLanguage = p.cfg.OCRLanguage
+	}
+	return fp, nil
+}
+
+// openManifest returns the manifest to record videoPath's progress in, or nil when checkpointing
+// is disabled. The existing manifest is reused only in resume mode and when its fingerprint
+// still matches; otherwise a fresh one is started.
+func (p *Pipeline) openManifest(ctx context.Context, videoPath string) (*checkpoint.Manifest, string) {
+	if p.cfg.ManifestPath == nil {
+		return nil, ""
+	}
+	path := p.cfg.ManifestPath(videoPath)
+	if path == "" {
+		return nil, ""
+	}
+	fp, err := p.fingerprint(videoPath)
+	if err != nil {
+		progress.Printf(ctx, "Checkpointing disabled for %s: %v", videoPath, err)
+		return nil, ""
+	}
+	if p.cfg.Resume {
+		m, err := checkpoint.Load(path)
+		switch {
+		case err == nil && m.Valid(fp):
+			progress.Printf(ctx, "Resuming video %s from %s (%d chunks recorded).", videoPath, path, len(m.Chunks))
+			return m, path
+		case err == nil:
+			progress.Printf(ctx, "Checkpoint %s is out of date (source, settings or prompt changed); starting over.", path)
+		case !errors.Is(err, os.ErrNotExist):
+			progress.Printf(ctx, "Warning: %v", err)
+		}
+	}
+	return checkpoint.New(fp), path
+}
+
+// saveManifest writes m, reporting rather than failing the run on error.
+func saveManifest(ctx context.Context, m *checkpoint.Manifest, path string) {
+	if err := m.Save(path); err != nil {
+		progress.Printf(ctx, "Warning: %v", err)
+	}
+}
+
+// checkpointChunk converts a chunk result to its manifest entry.
+func checkpointChunk(c ChunkResult) *checkpoint.Chunk {
+	entry := &checkpoint.Chunk{
+		Index:           c.ChunkNum,
+		Status:          checkpoint.ChunkDone,
+		Start:           c.Start,
+		End:             c.End,
+		AudioTranscript: c.AudioTranscript,
+		AudioSegments:   c.AudioSegments,
+		AudioLanguage:   c.AudioLanguage,
+		VideoTranscript: c.VideoTranscript,
+		VideoBackend:    c.VideoBackend,
+	}
+	for _, err := range []error{c.AudioErr, c.VideoErr} {
+		if err != nil {
+			entry.Status = checkpoint.ChunkFailed
+			entry.Errors = append(entry.Errors, err.Error())
+		}
+	}
+	return entry
+}
+
+// restoreChunk converts a completed manifest entry back to a chunk result.
+func restoreChunk(entry *checkpoint.Chunk) ChunkResult {
+	return ChunkResult{
+		ChunkNum:        entry.Index,
+		Start:           entry.Start,
+		End:             entry.End,
+		AudioTranscript: entry.AudioTranscript,
+		AudioSegments:   entry.AudioSegments,
+		AudioLanguage:   entry.AudioLanguage,
+		VideoTranscript: entry.VideoTranscript,
+		VideoBackend:    entry.VideoBackend,
+	}
+}
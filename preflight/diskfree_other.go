@@ -0,0 +1,10 @@
+//go:build !linux && !darwin
+
+package preflight
+
+import "errors"
+
+// diskFree is not implemented on this platform.
+func diskFree(dir string) (uint64, error) {
+	return 0, errors.ErrUnsupported
+}
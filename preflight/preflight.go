@@ -0,0 +1,431 @@
+// Package preflight checks that the external tools, models, credentials and disk space a
+// pipeline configuration needs are available before any video is processed.
+package preflight
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/utkarsh-cpu/videoSummaryGo/failure"
+	"github.com/utkarsh-cpu/videoSummaryGo/llm"
+	"github.com/utkarsh-cpu/videoSummaryGo/media"
+	"github.com/utkarsh-cpu/videoSummaryGo/output"
+	"github.com/utkarsh-cpu/videoSummaryGo/pipeline"
+	"github.com/utkarsh-cpu/videoSummaryGo/visual"
+)
+
+// Status is the outcome of a check.
+type Status string
+
+const (
+	OK   Status = "ok"
+	Warn Status = "warn"
+	Fail Status = "FAIL"
+)
+
+// Check is the result of one check. Fix, when set, says how to resolve a problem.
+type Check struct {
+	Name   string
+	Status Status
+	Detail string
+	Fix    string
+}
+
+// Report lists the checks in the order they ran.
+type Report []Check
+
+// Failed reports whether any check failed.
+func (r Report) Failed() bool {
+	for _, c := range r {
+		if c.Status == Fail {
+			return true
+		}
+	}
+	return false
+}
+
+// Problems returns the checks that did not pass.
+func (r Report) Problems() Report {
+	var problems Report
+	for _, c := range r {
+		if c.Status != OK {
+			problems = append(problems, c)
+		}
+	}
+	return problems
+}
+
+// Print writes one line per check, followed by the fix of each problem.
+func (r Report) Print(w io.Writer) {
+	for _, c := range r {
+		fmt.Fprintf(w, "%-4s  %-16s %s\n", c.Status, c.Name, c.Detail)
+		if c.Status != OK && c.Fix != "" {
+			fmt.Fprintf(w, "      %-16s fix: %s\n", "", c.Fix)
+		}
+	}
+}
+
+// Options describe the run being checked beyond its pipeline.Config.
+type Options struct {
+	// Input is the video or folder to be processed; its largest video sizes the disk space check.
+	Input string
+	// Embed is the subtitle embedding mode, whose ffmpeg encoders and filters are checked too.
+	Embed output.EmbedMode
+}
+
+// MinFreeSpace is the free space wanted in the temp directory on top of the largest input video,
+// which is roughly what its chunks and their WAV audio take up.
+const MinFreeSpace = 1 << 30
+
+// minFFmpegMajor is the oldest ffmpeg release the pipeline is used with.
+const minFFmpegMajor = 4
+
+// checkTimeout bounds each external command and the LLM ping.
+const checkTimeout = 20 * time.Second
+
+// Run checks everything cfg needs: ffmpeg and ffprobe with the required encoders, whisper-cli
+// and its model, tesseract and its language packs, the LLM credentials and the temp directory's
+// free space. Stages that cfg skips, replaces with custom implementations or serves from a
+// cache-only store are not checked.
+func Run(ctx context.Context, cfg pipeline.Config, opts Options) Report {
+	var r Report
+	r = append(r, checkFFmpeg(ctx, opts.Embed, cfg.SilenceWindow > 0)...)
+	r = append(r, checkFFprobe(ctx))
+
+	cacheOnly := cfg.Cache != nil && cfg.Cache.CacheOnly
+	if !cfg.SkipAudio && cfg.AudioTranscriber == nil && !cacheOnly {
+		r = append(r, checkWhisperCLI(ctx, cfg.WhisperCLIPath), checkWhisperModel(cfg.WhisperModelPath))
+	}
+	if !cfg.SkipVisual && !cacheOnly {
+		_, tesseractOnly := cfg.VisualTranscriber.(*visual.TesseractFramesTranscriber)
+		switch {
+		case tesseractOnly:
+			r = append(r, checkTesseract(ctx, cfg.OCRLanguage, Fail)...)
+		case cfg.VisualTranscriber == nil:
+			// Tesseract is only the fallback of the LLM, so problems with it are warnings.
+			r = append(r, checkTesseract(ctx, cfg.OCRLanguage, Warn)...)
+		}
+	}
+	if needsLLM(cfg) && !cacheOnly {
+		r = append(r, checkLLM(ctx, cfg))
+	}
+	r = append(r, checkTempDir(opts.Input))
+	return r
+}
+
+// needsLLM mirrors pipeline.New's decision to connect to the LLM.
+func needsLLM(cfg pipeline.Config) bool {
+	return !cfg.SkipSummary || (!cfg.SkipVisual && cfg.VisualTranscriber == nil)
+}
+
+// checkFFmpeg checks the ffmpeg version and the encoders and filters used for chunking, frame
+// extraction, silence detection and subtitle embedding.
+func checkFFmpeg(ctx context.Context, embed output.EmbedMode, silences bool) []Check {
+	const name = "ffmpeg"
+	path, version, check := checkTool(ctx, name, "-version", Fail, "install ffmpeg, e.g. apt install ffmpeg or brew install ffmpeg")
+	if check.Status != OK {
+		return []Check{check}
+	}
+	if major, ok := ffmpegMajor(version); ok && major < minFFmpegMajor {
+		check.Status = Warn
+		check.Fix = fmt.Sprintf("upgrade to ffmpeg %d or newer", minFFmpegMajor)
+	}
+	checks := []Check{check}
+
+	encoders, err := listNames(ctx, path, "-hide_banner", "-encoders")
+	if err != nil {
+		return append(checks, Check{Name: "ffmpeg encoders", Status: Warn, Detail: fmt.Sprintf("could not list encoders: %v", err)})
+	}
+	filters, err := listNames(ctx, path, "-hide_banner", "-filters")
+	if err != nil {
+		return append(checks, Check{Name: "ffmpeg filters", Status: Warn, Detail: fmt.Sprintf("could not list filters: %v", err)})
+	}
+	// pcm_s16le writes the WAV audio chunks for whisper, mjpeg the frames for OCR.
+	checks = append(checks, checkNames("ffmpeg encoders", "encoder", []string{"pcm_s16le", "mjpeg"}, encoders))
+	if silences {
+		checks = append(checks, checkNames("ffmpeg silences", "filter", []string{"silencedetect"}, filters))
+	}
+
+	switch embed {
+	case output.EmbedSoftMKV:
+		checks = append(checks, checkNames("ffmpeg embed", "encoder", []string{"srt"}, encoders))
+	case output.EmbedSoftMP4:
+		checks = append(checks, checkNames("ffmpeg embed", "encoder", []string{"mov_text"}, encoders))
+	case output.EmbedBurn:
+		checks = append(checks, checkNames("ffmpeg embed", "filter", []string{"subtitles"}, filters))
+	}
+	return checks
+}
+
+// checkNames fails when any of want is missing from have.
+func checkNames(name string, what string, want []string, have map[string]bool) Check {
+	var missing []string
+	for _, w := range want {
+		if !have[w] {
+			missing = append(missing, w)
+		}
+	}
+	if len(missing) > 0 {
+		return Check{
+			Name:   name,
+			Status: Fail,
+			Detail: fmt.Sprintf("missing %s %s", what, strings.Join(missing, ", ")),
+			Fix:    "install a full ffmpeg build (the subtitles filter needs libass)",
+		}
+	}
+	return Check{Name: name, Status: OK, Detail: strings.Join(want, ", ")}
+}
+
+// ffmpegMajor parses the major version from "ffmpeg version 6.1.1-3ubuntu5 ..." or "ffmpeg
+// version n7.0 ...". Builds from git, e.g. "N-112233-g...", have none.
+func ffmpegMajor(version string) (int, bool) {
+	_, v, ok := strings.Cut(version, "version ")
+	if !ok {
+		return 0, false
+	}
+	v = strings.TrimPrefix(v, "n")
+	end := strings.IndexFunc(v, func(r rune) bool { return r < '0' || r > '9' })
+	if end < 0 {
+		end = len(v)
+	}
+	major, err := strconv.Atoi(v[:end])
+	return major, err == nil
+}
+
+func checkFFprobe(ctx context.Context) Check {
+	_, _, check := checkTool(ctx, "ffprobe", "-version", Fail, "install ffmpeg, which includes ffprobe")
+	return check
+}
+
+// checkTool looks up name and runs it with versionArg. It returns the tool's path, the first
+// line of its version output and the check, whose status is failStatus if the tool is missing.
+func checkTool(ctx context.Context, name string, versionArg string, failStatus Status, fix string) (string, string, Check) {
+	path, err := exec.LookPath(name)
+	if err != nil {
+		return "", "", Check{Name: name, Status: failStatus, Detail: "not found in PATH", Fix: fix}
+	}
+	out, err := runTool(ctx, path, versionArg)
+	if err != nil {
+		return path, "", Check{Name: name, Status: failStatus, Detail: fmt.Sprintf("%s does not run: %v", path, err), Fix: "reinstall " + name}
+	}
+	version, _, _ := strings.Cut(strings.TrimSpace(out), "\n")
+	version, _, _ = strings.Cut(version, " Copyright")
+	return path, version, Check{Name: name, Status: OK, Detail: fmt.Sprintf("%s (%s)", version, path)}
+}
+
+// runTool runs path with args and returns its combined output.
+func runTool(ctx context.Context, path string, args ...string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, checkTimeout)
+	defer cancel()
+	out, err := exec.CommandContext(ctx, path, args...).CombinedOutput()
+	return string(out), err
+}
+
+// listNames runs an ffmpeg listing such as -encoders and returns the names in its second column.
+func listNames(ctx context.Context, path string, args ...string) (map[string]bool, error) {
+	out, err := runTool(ctx, path, args...)
+	if err != nil {
+		return nil, err
+	}
+	names := map[string]bool{}
+	for _, line := range strings.Split(out, "\n") {
+		if fields := strings.Fields(line); len(fields) >= 2 {
+			names[fields[1]] = true
+		}
+	}
+	return names, nil
+}
+
+// checkWhisperCLI checks that the whisper-cli binary exists and runs.
+func checkWhisperCLI(ctx context.Context, path string) Check {
+	const name = "whisper-cli"
+	fix := "build whisper.cpp (cmake -B build && cmake --build build --config Release) or pass --whisper-cli"
+	resolved, err := exec.LookPath(path)
+	if err != nil {
+		return Check{Name: name, Status: Fail, Detail: fmt.Sprintf("%s not found or not executable", path), Fix: fix}
+	}
+	out, err := runTool(ctx, resolved, "--help")
+	var exitErr *exec.ExitError
+	if err != nil && !errors.As(err, &exitErr) {
+		return Check{Name: name, Status: Fail, Detail: fmt.Sprintf("%s does not run: %v", resolved, err), Fix: fix}
+	}
+	if !strings.Contains(strings.ToLower(out), "usage") {
+		return Check{Name: name, Status: Warn, Detail: fmt.Sprintf("%s did not print whisper-cli usage", resolved), Fix: "check that --whisper-cli points to whisper.cpp's whisper-cli"}
+	}
+	return Check{Name: name, Status: OK, Detail: resolved}
+}
+
+// checkWhisperModel checks that the model file exists and starts with the ggml or GGUF magic.
+func checkWhisperModel(path string) Check {
+	const name = "whisper model"
+	fix := "download a model with ./whisper.cpp/models/download-ggml-model.sh medium.en or pass --whisper-model"
+	f, err := os.Open(path)
+	if err != nil {
+		return Check{Name: name, Status: Fail, Detail: err.Error(), Fix: fix}
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return Check{Name: name, Status: Fail, Detail: err.Error(), Fix: fix}
+	}
+	magic := make([]byte, 4)
+	if _, err := io.ReadFull(f, magic); err != nil {
+		return Check{Name: name, Status: Fail, Detail: fmt.Sprintf("%s is empty or unreadable: %v", path, err), Fix: fix}
+	}
+	// ggml files start with the little-endian uint32 0x67676d6c.
+	if !bytes.Equal(magic, []byte("lmgg")) && !bytes.Equal(magic, []byte("GGUF")) {
+		return Check{
+			Name:   name,
+			Status: Fail,
+			Detail: fmt.Sprintf("%s is not a ggml model (starts with %q)", path, magic),
+			Fix:    "download the model again; failed downloads often leave an HTML page or a Git LFS pointer",
+		}
+	}
+	if info.Size() < 1<<20 {
+		return Check{Name: name, Status: Fail, Detail: fmt.Sprintf("%s is only %s, probably truncated", path, formatBytes(uint64(info.Size()))), Fix: fix}
+	}
+	return Check{Name: name, Status: OK, Detail: fmt.Sprintf("%s (%s)", path, formatBytes(uint64(info.Size())))}
+}
+
+// checkTesseract checks the tesseract version and that the language packs in language, e.g.
+// "eng+deu" (default eng), are installed. Problems get failStatus.
+func checkTesseract(ctx context.Context, language string, failStatus Status) []Check {
+	path, _, check := checkTool(ctx, "tesseract", "--version", failStatus, "install tesseract, e.g. apt install tesseract-ocr or brew install tesseract")
+	if check.Status != OK {
+		return []Check{check}
+	}
+	out, err := runTool(ctx, path, "--list-langs")
+	if err != nil {
+		return []Check{check, {Name: "tesseract langs", Status: failStatus, Detail: fmt.Sprintf("could not list languages: %v", err)}}
+	}
+	installed := map[string]bool{}
+	for _, line := range strings.Split(out, "\n") {
+		if line = strings.TrimSpace(line); line != "" && !strings.HasPrefix(line, "List of available languages") {
+			installed[line] = true
+		}
+	}
+	if language == "" {
+		language = "eng"
+	}
+	var missing, packages []string
+	for _, lang := range strings.Split(language, "+") {
+		if !installed[lang] {
+			missing = append(missing, lang)
+			packages = append(packages, "tesseract-ocr-"+lang)
+		}
+	}
+	if len(missing) > 0 {
+		return []Check{check, {
+			Name:   "tesseract langs",
+			Status: failStatus,
+			Detail: "missing language packs: " + strings.Join(missing, ", "),
+			Fix:    fmt.Sprintf("install them, e.g. apt install %s, or put <lang>.traineddata in the tessdata directory", strings.Join(packages, " ")),
+		}}
+	}
+	return []Check{check, {Name: "tesseract langs", Status: OK, Detail: language}}
+}
+
+// checkLLM connects to the configured LLM and pings it.
+func checkLLM(ctx context.Context, cfg pipeline.Config) Check {
+	const name = "LLM"
+	client := cfg.LLMClient
+	if client == nil {
+		if (cfg.LLMBackend == "" || cfg.LLMBackend == "gemini") && cfg.APIKey == "" {
+			return Check{Name: name, Status: Fail, Detail: "no API key for gemini", Fix: "set $GEMINI_API_KEY or pass --api-key-file (see API keys in the README)"}
+		}
+		c, err := llm.New(ctx, llm.Options{Backend: cfg.LLMBackend, Model: cfg.LLM, APIKey: cfg.APIKey, BaseURL: cfg.LLMBaseURL})
+		if err != nil {
+			return Check{Name: name, Status: Fail, Detail: err.Error(), Fix: "check --model"}
+		}
+		defer c.Close()
+		client = c
+	}
+	desc := client.Name() + " " + client.Model()
+
+	pingCtx, cancel := context.WithTimeout(ctx, checkTimeout)
+	defer cancel()
+	if err := client.Ping(pingCtx); err != nil {
+		fix := "check the model name, --base-url and the network connection"
+		if failure.KindOf(err) == failure.LLMAuth {
+			fix = "check the API key (see API keys in the README)"
+		}
+		return Check{Name: name, Status: Fail, Detail: fmt.Sprintf("%s: %v", desc, err), Fix: fix}
+	}
+	return Check{Name: name, Status: OK, Detail: desc + " reachable"}
+}
+
+// checkTempDir checks that the temp directory, where chunks and frames are written, is writable
+// and has room for the largest video below input.
+func checkTempDir(input string) Check {
+	const name = "temp dir"
+	dir := os.TempDir()
+	fix := "free up space or point $TMPDIR to a larger disk"
+	probe, err := os.MkdirTemp(dir, "preflight")
+	if err != nil {
+		return Check{Name: name, Status: Fail, Detail: fmt.Sprintf("%s is not writable: %v", dir, err), Fix: "point $TMPDIR to a writable directory"}
+	}
+	os.Remove(probe)
+
+	free, err := diskFree(dir)
+	if err != nil {
+		return Check{Name: name, Status: OK, Detail: fmt.Sprintf("%s (free space not checked: %v)", dir, err)}
+	}
+	largest := largestVideo(input)
+	detail := fmt.Sprintf("%s has %s free", dir, formatBytes(free))
+	switch {
+	case free < largest:
+		return Check{Name: name, Status: Fail, Detail: fmt.Sprintf("%s, less than the largest video (%s)", detail, formatBytes(largest)), Fix: fix}
+	case free < largest+MinFreeSpace:
+		return Check{Name: name, Status: Warn, Detail: fmt.Sprintf("%s, want %s for the largest video's chunks", detail, formatBytes(largest+MinFreeSpace)), Fix: fix}
+	}
+	return Check{Name: name, Status: OK, Detail: detail}
+}
+
+// largestVideo returns the size of input if it is a file, or of the largest video below it.
+func largestVideo(input string) uint64 {
+	if input == "" {
+		return 0
+	}
+	info, err := os.Stat(input)
+	if err != nil {
+		return 0
+	}
+	if !info.IsDir() {
+		return uint64(info.Size())
+	}
+	var largest uint64
+	filepath.WalkDir(input, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || !media.IsVideoFile(path) {
+			return nil
+		}
+		if info, err := d.Info(); err == nil {
+			largest = max(largest, uint64(info.Size()))
+		}
+		return nil
+	})
+	return largest
+}
+
+// formatBytes formats n in binary units, e.g. "1.4 GiB".
+func formatBytes(n uint64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := uint64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
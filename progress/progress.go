@@ -0,0 +1,40 @@
+// Package progress lets library code report what it is doing without printing. Messages go to
+// the Logger carried by the context and are dropped when there is none.
+package progress
+
+import "context"
+
+// Logger receives progress messages. *log.Logger implements it.
+type Logger interface {
+	Printf(format string, v ...any)
+}
+
+// Discard is a Logger that drops every message.
+var Discard Logger = discard{}
+
+type discard struct{}
+
+func (discard) Printf(string, ...any) {}
+
+type loggerKey struct{}
+
+// NewContext returns a copy of ctx that carries l. A nil l leaves ctx unchanged.
+func NewContext(ctx context.Context, l Logger) context.Context {
+	if l == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, loggerKey{}, l)
+}
+
+// FromContext returns the Logger carried by ctx, or Discard.
+func FromContext(ctx context.Context) Logger {
+	if l, ok := ctx.Value(loggerKey{}).(Logger); ok {
+		return l
+	}
+	return Discard
+}
+
+// Printf reports a message to the Logger carried by ctx.
+func Printf(ctx context.Context, format string, v ...any) {
+	FromContext(ctx).Printf(format, v...)
+}
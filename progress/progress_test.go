@@ -0,0 +1,25 @@
+package progress
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"testing"
+)
+
+func TestContextLogger(t *testing.T) {
+	// Without a Logger, messages are dropped.
+	Printf(context.Background(), "dropped %d", 1)
+	if l := FromContext(context.Background()); l != Discard {
+		t.Errorf("FromContext(Background) = %v, want Discard", l)
+	}
+
+	var buf bytes.Buffer
+	ctx := NewContext(context.Background(), log.New(&buf, "", 0))
+	Printf(ctx, "chunk %d done", 3)
+	// A nil Logger keeps the one already carried.
+	Printf(NewContext(ctx, nil), "chunk %d done", 4)
+	if want := "chunk 3 done\nchunk 4 done\n"; buf.String() != want {
+		t.Errorf("logged %q, want %q", buf.String(), want)
+	}
+}
@@ -0,0 +1,46 @@
+package report
+
+import (
+	"embed"
+	"fmt"
+	"io"
+	"strings"
+	"text/template"
+)
+
+//go:embed templates/report.md.tmpl
+var templates embed.FS
+
+// Funcs are the helper functions available to report templates.
+var Funcs = template.FuncMap{
+	"clock":  Clock,
+	"anchor": Anchor,
+	"join":   strings.Join,
+}
+
+// DefaultTemplate returns the built-in Markdown report template.
+func DefaultTemplate() *template.Template {
+	return template.Must(template.New("report.md.tmpl").Funcs(Funcs).ParseFS(templates, "templates/report.md.tmpl"))
+}
+
+// ParseTemplate loads a custom report template from path. Templates are executed with *Data
+// and may use the helpers in Funcs.
+func ParseTemplate(path string) (*template.Template, error) {
+	tmpl, err := template.New("").Funcs(Funcs).ParseFiles(path)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing report template %s: %w", path, err)
+	}
+	// ParseFiles names the template after the file; execute that one rather than the empty root.
+	return tmpl.Templates()[0], nil
+}
+
+// RenderMarkdown executes tmpl with data, using DefaultTemplate when tmpl is nil.
+func RenderMarkdown(w io.Writer, data *Data, tmpl *template.Template) error {
+	if tmpl == nil {
+		tmpl = DefaultTemplate()
+	}
+	if err := tmpl.Execute(w, data); err != nil {
+		return fmt.Errorf("error rendering report: %w", err)
+	}
+	return nil
+}
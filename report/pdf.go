@@ -0,0 +1,161 @@
+package report
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/jung-kurt/gofpdf"
+	"golang.org/x/text/encoding/charmap"
+)
+
+// Page layout in millimetres and points.
+const (
+	pdfMargin     = 20.0
+	pdfBodySize   = 11.0
+	pdfCodeSize   = 9.0
+	pdfLineHeight = 5.5
+	pdfCodeHeight = 4.2
+)
+
+// headingSizes are the font sizes of #, ## and ### headings.
+var headingSizes = []float64{20, 15, 12.5}
+
+var (
+	orderedItem = regexp.MustCompile(`^(\d+)\.\s+(.*)$`)
+	inlineLink  = regexp.MustCompile(`\[([^\]]*)\]\([^)]*\)`)
+	inlineMarks = strings.NewReplacer("**", "", "__", "", "`", "")
+)
+
+// RenderPDF renders the Markdown produced by RenderMarkdown as a PDF. It supports the subset
+// the report templates use: headings (which also become PDF bookmarks), paragraphs, bullet and
+// numbered lists, fenced code blocks and horizontal rules. Inline emphasis and link targets are
+// dropped. Text outside Windows-1252 is not representable with the built-in fonts (see
+// Unrepresentable), so the Markdown report is the complete one.
+func RenderPDF(w io.Writer, markdown string, title string) error {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	tr := pdf.UnicodeTranslatorFromDescriptor("")
+	pdf.SetTitle(title, true)
+	pdf.SetCreator("videoSummaryGo", true)
+	pdf.SetMargins(pdfMargin, pdfMargin, pdfMargin)
+	pdf.SetAutoPageBreak(true, pdfMargin)
+	pdf.AliasNbPages("")
+	pdf.SetFooterFunc(func() {
+		pdf.SetY(-pdfMargin + 5)
+		pdf.SetFont("Helvetica", "I", 8)
+		pdf.CellFormat(0, 5, fmt.Sprintf("%d / {nb}", pdf.PageNo()), "", 0, "C", false, 0, "")
+	})
+	pdf.AddPage()
+
+	pageWidth, _ := pdf.GetPageSize()
+	inCode := false
+	var paragraph []string
+
+	flush := func() {
+		if len(paragraph) == 0 {
+			return
+		}
+		pdf.SetFont("Helvetica", "", pdfBodySize)
+		pdf.MultiCell(0, pdfLineHeight, tr(inline(strings.Join(paragraph, " "))), "", "L", false)
+		pdf.Ln(2)
+		paragraph = nil
+	}
+
+	scanner := bufio.NewScanner(strings.NewReader(markdown))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		trimmed := strings.TrimSpace(line)
+
+		if strings.HasPrefix(trimmed, "```") {
+			flush()
+			inCode = !inCode
+			if !inCode {
+				pdf.Ln(2)
+			}
+			continue
+		}
+		if inCode {
+			pdf.SetFont("Courier", "", pdfCodeSize)
+			pdf.MultiCell(0, pdfCodeHeight, tr(line), "", "L", false)
+			continue
+		}
+
+		switch {
+		case trimmed == "":
+			flush()
+		case strings.HasPrefix(trimmed, "#"):
+			flush()
+			level := len(trimmed) - len(strings.TrimLeft(trimmed, "#"))
+			text := inline(strings.TrimSpace(trimmed[level:]))
+			size := headingSizes[min(level, len(headingSizes))-1]
+			pdf.Ln(2)
+			pdf.Bookmark(tr(text), min(level-1, 2), -1)
+			pdf.SetFont("Helvetica", "B", size)
+			pdf.MultiCell(0, size*0.5, tr(text), "", "L", false)
+			pdf.Ln(2)
+		case trimmed == "---" || trimmed == "***":
+			flush()
+			y := pdf.GetY() + 2
+			pdf.SetDrawColor(180, 180, 180)
+			pdf.Line(pdfMargin, y, pageWidth-pdfMargin, y)
+			pdf.Ln(5)
+		case strings.HasPrefix(trimmed, "- ") || strings.HasPrefix(trimmed, "* "):
+			flush()
+			listItem(pdf, tr, "\x95", inline(trimmed[2:]), indentOf(line))
+		case orderedItem.MatchString(trimmed):
+			flush()
+			m := orderedItem.FindStringSubmatch(trimmed)
+			listItem(pdf, tr, m[1]+".", inline(m[2]), indentOf(line))
+		default:
+			paragraph = append(paragraph, trimmed)
+		}
+	}
+	flush()
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("error reading report markdown: %w", err)
+	}
+	if err := pdf.Output(w); err != nil {
+		return fmt.Errorf("error rendering PDF: %w", err)
+	}
+	return nil
+}
+
+// Unrepresentable returns the distinct characters of markdown, in order of first appearance, that
+// RenderPDF cannot set because they are outside Windows-1252, such as Greek, Cyrillic or Chinese.
+// They appear as dots in the PDF; the Markdown report keeps them.
+func Unrepresentable(markdown string) []rune {
+	var missing []rune
+	seen := map[rune]bool{}
+	for _, r := range markdown {
+		if _, ok := charmap.Windows1252.EncodeRune(r); ok || seen[r] {
+			continue
+		}
+		seen[r] = true
+		missing = append(missing, r)
+	}
+	return missing
+}
+
+// listItem writes a bullet or numbered item with a hanging indent.
+func listItem(pdf *gofpdf.Fpdf, tr func(string) string, marker string, text string, indent int) {
+	left := pdfMargin + 4 + float64(indent)*2
+	pdf.SetFont("Helvetica", "", pdfBodySize)
+	pdf.SetX(left)
+	pdf.CellFormat(6, pdfLineHeight, tr(marker), "", 0, "L", false, 0, "")
+	pdf.SetLeftMargin(left + 6)
+	pdf.MultiCell(0, pdfLineHeight, tr(text), "", "L", false)
+	pdf.SetLeftMargin(pdfMargin)
+}
+
+// indentOf counts the leading spaces of line.
+func indentOf(line string) int {
+	return len(line) - len(strings.TrimLeft(line, " "))
+}
+
+// inline strips Markdown emphasis and link targets from text.
+func inline(text string) string {
+	return inlineMarks.Replace(inlineLink.ReplaceAllString(text, "$1"))
+}
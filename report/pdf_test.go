@@ -0,0 +1,79 @@
+package report
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestUnrepresentable(t *testing.T) {
+	if got := Unrepresentable("Größe, café — “quoted” • 1–2 ·\tü\n"); len(got) != 0 {
+		t.Errorf("Unrepresentable of Windows-1252 text = %q, want none", string(got))
+	}
+	// Each character is reported once, in order of appearance.
+	if got, want := Unrepresentable("x ≤ y, 你好, 你"), []rune("≤你好"); !slices.Equal(got, want) {
+		t.Errorf("Unrepresentable = %q, want %q", string(got), string(want))
+	}
+}
+
+func TestRenderPDF(t *testing.T) {
+	markdown := "# Lecture 1: Größen\n\nSummary: “done” — café.\n\n- point one\n1. first\n\n```\n[00:00:01.000 --> 00:00:02.000]  Hello\n```\n"
+	var buf bytes.Buffer
+	if err := RenderPDF(&buf, markdown, "Lecture"); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.HasPrefix(buf.Bytes(), []byte("%PDF-")) {
+		t.Fatalf("output does not start with a PDF header: %q", buf.Bytes()[:min(buf.Len(), 16)])
+	}
+}
+
+func TestDefaultTemplateSeparatesSections(t *testing.T) {
+	data := &Data{
+		Title:    "Talk",
+		Chapters: []Chapter{{Number: 1, Title: "Intro"}},
+		Failures: []Failure{{Stage: "audio", Chunk: 2, Kind: "whisper", Message: "failed"}},
+		Chunks:   []Chunk{{Number: 1, Audio: "hello", Visual: "slide"}},
+	}
+	for _, failures := range [][]Failure{data.Failures, nil} {
+		data.Failures = failures
+		var b strings.Builder
+		if err := RenderMarkdown(&b, data, nil); err != nil {
+			t.Fatal(err)
+		}
+		headings := []string{"## Chapters", "## Appendix A: Audio transcript", "## Appendix B: On-screen text"}
+		if failures != nil {
+			headings = append(headings, "## Problems")
+		}
+		for _, h := range headings {
+			if !strings.Contains(b.String(), "\n\n"+h+"\n") {
+				t.Errorf("failures=%d: %q is not preceded by a blank line in\n%s", len(failures), h, b.String())
+			}
+		}
+	}
+}
+
+func TestParseTemplate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "custom.md.tmpl")
+	if err := os.WriteFile(path, []byte("# {{.Title}}\n\nDuration {{clock .Source.Duration}}\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	tmpl, err := ParseTemplate(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var b strings.Builder
+	if err := RenderMarkdown(&b, &Data{Title: "Talk", Source: Source{Duration: 61 * time.Second}}, tmpl); err != nil {
+		t.Fatal(err)
+	}
+	if want := "# Talk\n\nDuration 1:01\n"; b.String() != want {
+		t.Errorf("rendered %q, want %q", b.String(), want)
+	}
+
+	if _, err := ParseTemplate(filepath.Join(t.TempDir(), "missing.tmpl")); err == nil {
+		t.Error("ParseTemplate of a missing file succeeded")
+	}
+}
@@ -0,0 +1,156 @@
+package report
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/utkarsh-cpu/videoSummaryGo/pipeline"
+)
+
+// chapterTitleLength is the maximum length of a chapter title taken from the transcript.
+const chapterTitleLength = 60
+
+// Data is what report templates are executed with.
+type Data struct {
+	Title     string
+	Generated time.Time
+	Source    Source
+	Summary   string
+	Chapters  []Chapter
+	Chunks    []Chunk
+	Failures  []Failure
+}
+
+// Failure is one row of the report's failure table.
+type Failure struct {
+	Stage   string
+	Chunk   int // 1-based part number, 0 when not tied to a chunk
+	Kind    string
+	Message string
+}
+
+// Source describes the input video.
+type Source struct {
+	Path       string
+	Duration   time.Duration
+	Format     string
+	Resolution string
+	Backends   []string // visual transcription backends that produced text
+}
+
+// Chapter is a navigation entry pointing at a position in the video.
+type Chapter struct {
+	Number int
+	Start  time.Duration
+	End    time.Duration
+	Title  string
+}
+
+// Chunk is the transcript of one chunk for the appendices.
+type Chunk struct {
+	Number        int
+	Start         time.Duration
+	End           time.Duration
+	Audio         string
+	Visual        string
+	VisualBackend string
+}
+
+// NewData builds report data from a pipeline result. Each chunk becomes a chapter titled
+// with the opening words spoken in it.
+func NewData(result *pipeline.VideoResult) *Data {
+	data := &Data{
+		Title:     result.BaseName,
+		Generated: time.Now(),
+		Source:    Source{Path: result.VideoPath},
+		Summary:   demoteHeadings(strings.TrimSpace(result.Summary)),
+	}
+	if result.Probe != nil {
+		data.Source.Duration = result.Probe.Duration()
+		data.Source.Format = result.Probe.Format.FormatName
+		for _, s := range result.Probe.Streams {
+			if s.CodecType == "video" && s.Width > 0 {
+				data.Source.Resolution = fmt.Sprintf("%dx%d", s.Width, s.Height)
+				break
+			}
+		}
+	}
+
+	seenBackend := map[string]bool{}
+	for _, c := range result.Chunks {
+		if c.VideoBackend != "" && !seenBackend[c.VideoBackend] {
+			seenBackend[c.VideoBackend] = true
+			data.Source.Backends = append(data.Source.Backends, c.VideoBackend)
+		}
+		title := ""
+		if len(c.AudioSegments) > 0 {
+			title = chapterTitle(c.AudioSegments[0].Text)
+		}
+		if title == "" {
+			title = fmt.Sprintf("Part %d", c.ChunkNum+1)
+		}
+		data.Chapters = append(data.Chapters, Chapter{Number: c.ChunkNum + 1, Start: c.Start, End: c.End, Title: title})
+		data.Chunks = append(data.Chunks, Chunk{
+			Number:        c.ChunkNum + 1,
+			Start:         c.Start,
+			End:           c.End,
+			Audio:         strings.TrimSpace(c.AudioTranscript),
+			Visual:        strings.TrimSpace(c.VideoTranscript),
+			VisualBackend: c.VideoBackend,
+		})
+	}
+	for _, f := range result.Failures {
+		data.Failures = append(data.Failures, Failure{Stage: f.Stage, Chunk: f.Chunk + 1, Kind: string(f.Kind()), Message: f.Err.Error()})
+	}
+	return data
+}
+
+// demoteHeadings pushes Markdown headings in text two levels down so the summary's own
+// headings nest under the report's "Summary" section.
+func demoteHeadings(text string) string {
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		if strings.HasPrefix(line, "#") && !strings.HasPrefix(line, "#####") {
+			lines[i] = "##" + line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// chapterTitle shortens text to chapterTitleLength characters at a word boundary.
+func chapterTitle(text string) string {
+	text = strings.TrimSpace(text)
+	if len(text) <= chapterTitleLength {
+		return text
+	}
+	cut := strings.LastIndexFunc(text[:chapterTitleLength], unicode.IsSpace)
+	if cut <= 0 {
+		cut = chapterTitleLength
+	}
+	return strings.TrimSpace(text[:cut]) + "…"
+}
+
+// Clock formats d as h:mm:ss, or m:ss under an hour.
+func Clock(d time.Duration) string {
+	s := int(d.Round(time.Second).Seconds())
+	if s >= 3600 {
+		return fmt.Sprintf("%d:%02d:%02d", s/3600, s/60%60, s%60)
+	}
+	return fmt.Sprintf("%d:%02d", s/60, s%60)
+}
+
+// Anchor returns the GitHub-style heading anchor for title.
+func Anchor(title string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(title) {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r) || r == '-' || r == '_':
+			b.WriteRune(r)
+		case r == ' ':
+			b.WriteRune('-')
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,171 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"syscall"
+	"time"
+
+	"github.com/utkarsh-cpu/videoSummaryGo/failure"
+	"github.com/utkarsh-cpu/videoSummaryGo/progress"
+)
+
+// Defaults used when the corresponding Policy field is zero.
+const (
+	DefaultMaxAttempts  = 4
+	DefaultInitialDelay = 5 * time.Second
+	DefaultMaxDelay     = 2 * time.Minute
+	DefaultMultiplier   = 2.0
+	DefaultJitter       = 0.2
+)
+
+// StatusCoder is implemented by errors that carry an HTTP status code.
+type StatusCoder interface {
+	HTTPStatus() int
+}
+
+// DelayHinter is implemented by errors that carry a server hint for when to retry,
+// such as a Retry-After header. A zero delay means no hint.
+type DelayHinter interface {
+	RetryDelay() time.Duration
+}
+
+// Policy retries an operation with exponential backoff and jitter. The zero value uses the defaults.
+type Policy struct {
+	MaxAttempts  int           // total attempts including the first
+	InitialDelay time.Duration // delay before the second attempt
+	MaxDelay     time.Duration // cap for the backoff and for server retry hints
+	Multiplier   float64       // backoff growth per attempt
+	Jitter       float64       // random +/- fraction applied to each backoff delay; negative disables it
+	// Retryable decides whether an error is worth retrying (default Retryable).
+	Retryable func(error) bool
+}
+
+// withDefaults fills zero fields with the package defaults.
+func (p Policy) withDefaults() Policy {
+	if p.MaxAttempts <= 0 {
+		p.MaxAttempts = DefaultMaxAttempts
+	}
+	if p.InitialDelay <= 0 {
+		p.InitialDelay = DefaultInitialDelay
+	}
+	if p.MaxDelay <= 0 {
+		p.MaxDelay = DefaultMaxDelay
+	}
+	if p.Multiplier < 1 {
+		p.Multiplier = DefaultMultiplier
+	}
+	if p.Jitter == 0 {
+		p.Jitter = DefaultJitter
+	}
+	if p.Retryable == nil {
+		p.Retryable = Retryable
+	}
+	return p
+}
+
+// Delay returns how long to wait after the given failed attempt (1-based): the server's hint
+// if err carries one, otherwise the jittered exponential backoff, capped at MaxDelay.
+func (p Policy) Delay(attempt int, err error) time.Duration {
+	p = p.withDefaults()
+	var hinter DelayHinter
+	if errors.As(err, &hinter) && hinter.RetryDelay() > 0 {
+		return min(hinter.RetryDelay(), p.MaxDelay)
+	}
+	delay := float64(p.InitialDelay)
+	for i := 1; i < attempt; i++ {
+		delay *= p.Multiplier
+	}
+	if p.Jitter > 0 {
+		delay *= 1 + p.Jitter*(2*rand.Float64()-1)
+	}
+	return min(time.Duration(delay), p.MaxDelay)
+}
+
+// Do calls fn until it succeeds, returns an error that is not retryable, MaxAttempts is reached
+// or ctx is done. op names the operation in log messages. The last error is returned.
+func (p Policy) Do(ctx context.Context, op string, fn func() error) error {
+	p = p.withDefaults()
+	for attempt := 1; ; attempt++ {
+		err := fn()
+		if err == nil {
+			return nil
+		}
+		if ctx.Err() != nil {
+			return err
+		}
+		var perm *permanentError
+		if errors.As(err, &perm) {
+			return perm.err
+		}
+		if !p.Retryable(err) {
+			progress.Printf(ctx, "%s failed with a non-retryable error: %v", op, err)
+			return err
+		}
+		if attempt >= p.MaxAttempts {
+			return fmt.Errorf("giving up after %d attempts: %w", attempt, err)
+		}
+		delay := p.Delay(attempt, err)
+		progress.Printf(ctx, "%s failed (attempt %d of %d): %v", op, attempt, p.MaxAttempts, err)
+		progress.Printf(ctx, "Retrying in %v...", delay.Round(time.Millisecond))
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return err
+		}
+	}
+}
+
+// permanentError marks an error that must not be retried.
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string {
+	return e.err.Error()
+}
+
+func (e *permanentError) Unwrap() error {
+	return e.err
+}
+
+// Permanent wraps err so that Do returns it without retrying, whatever the policy's Retryable says.
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &permanentError{err: err}
+}
+
+// Retryable reports whether err is transient: HTTP 408, 429 and 5xx responses, timeouts and
+// dropped connections. Everything else, notably cancellation, bad credentials and blocked
+// content, fails fast.
+func Retryable(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) {
+		return false
+	}
+	switch failure.KindOf(err) {
+	case failure.LLMAuth, failure.LLMBlocked, failure.ToolMissing, failure.Interrupted:
+		return false
+	case failure.LLMQuota:
+		return true
+	}
+	var status StatusCoder
+	if errors.As(err, &status) {
+		code := status.HTTPStatus()
+		return code == http.StatusRequestTimeout || code == http.StatusTooManyRequests || code >= 500
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return true // timeouts, refused and reset connections
+	}
+	return errors.Is(err, context.DeadlineExceeded) ||
+		errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, syscall.ECONNREFUSED)
+}
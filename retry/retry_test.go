@@ -0,0 +1,169 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/utkarsh-cpu/videoSummaryGo/failure"
+)
+
+type statusError int
+
+func (e statusError) Error() string   { return fmt.Sprintf("status %d", int(e)) }
+func (e statusError) HTTPStatus() int { return int(e) }
+
+type hintError time.Duration
+
+func (e hintError) Error() string             { return "slow down" }
+func (e hintError) RetryDelay() time.Duration { return time.Duration(e) }
+func (e hintError) HTTPStatus() int           { return 429 }
+
+func TestDo(t *testing.T) {
+	errFatal := errors.New("fatal")
+	tests := []struct {
+		name      string
+		errs      []error // returned by successive calls; nil once exhausted
+		retryable func(error) bool
+		wantCalls int
+		wantErr   error
+	}{
+		{"success", nil, nil, 1, nil},
+		{"transient then success", []error{io.ErrUnexpectedEOF, statusError(503)}, nil, 3, nil},
+		{"gives up", []error{io.ErrUnexpectedEOF, io.ErrUnexpectedEOF, io.ErrUnexpectedEOF, io.ErrUnexpectedEOF}, nil, 3, io.ErrUnexpectedEOF},
+		{"not retryable", []error{errFatal}, nil, 1, errFatal},
+		{"permanent", []error{Permanent(io.ErrUnexpectedEOF)}, nil, 1, io.ErrUnexpectedEOF},
+		{"custom retryable", []error{errFatal, errFatal}, func(err error) bool { return errors.Is(err, errFatal) }, 3, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := Policy{MaxAttempts: 3, InitialDelay: time.Millisecond, Jitter: -1, Retryable: tt.retryable}
+			calls := 0
+			err := p.Do(context.Background(), "test", func() error {
+				calls++
+				if calls <= len(tt.errs) {
+					return tt.errs[calls-1]
+				}
+				return nil
+			})
+			if calls != tt.wantCalls {
+				t.Errorf("calls = %d, want %d", calls, tt.wantCalls)
+			}
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("err = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestDoPermanentIsUnwrapped(t *testing.T) {
+	err := Policy{}.Do(context.Background(), "test", func() error { return Permanent(io.ErrUnexpectedEOF) })
+	var perm *permanentError
+	if errors.As(err, &perm) {
+		t.Errorf("Do returned the Permanent wrapper: %v", err)
+	}
+	if Permanent(nil) != nil {
+		t.Error("Permanent(nil) != nil")
+	}
+}
+
+func TestDoCancelledDuringBackoff(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	p := Policy{MaxAttempts: 5, InitialDelay: time.Hour, Jitter: -1}
+	calls := 0
+	start := time.Now()
+	err := p.Do(ctx, "test", func() error {
+		calls++
+		time.AfterFunc(10*time.Millisecond, cancel)
+		return io.ErrUnexpectedEOF
+	})
+	if calls != 1 {
+		t.Errorf("calls = %d, want 1", calls)
+	}
+	if !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("err = %v, want the last attempt's error", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("Do waited %v after cancellation", elapsed)
+	}
+}
+
+func TestDoCancelledDuringAttempt(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	calls := 0
+	err := Policy{InitialDelay: time.Millisecond}.Do(ctx, "test", func() error {
+		calls++
+		cancel()
+		return io.ErrUnexpectedEOF
+	})
+	if calls != 1 || !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("Do = %v after %d calls, want the first error after one call", err, calls)
+	}
+}
+
+func TestDelay(t *testing.T) {
+	p := Policy{InitialDelay: 10 * time.Millisecond, MaxDelay: time.Second, Multiplier: 3, Jitter: -1}
+	tests := []struct {
+		name    string
+		attempt int
+		err     error
+		want    time.Duration
+	}{
+		{"first", 1, io.ErrUnexpectedEOF, 10 * time.Millisecond},
+		{"second", 2, io.ErrUnexpectedEOF, 30 * time.Millisecond},
+		{"third", 3, io.ErrUnexpectedEOF, 90 * time.Millisecond},
+		{"capped", 10, io.ErrUnexpectedEOF, time.Second},
+		{"hint", 1, hintError(400 * time.Millisecond), 400 * time.Millisecond},
+		{"wrapped hint", 3, fmt.Errorf("calling: %w", hintError(200*time.Millisecond)), 200 * time.Millisecond},
+		{"hint capped", 1, hintError(time.Hour), time.Second},
+		{"zero hint", 2, hintError(0), 30 * time.Millisecond},
+	}
+	for _, tt := range tests {
+		if got := p.Delay(tt.attempt, tt.err); got != tt.want {
+			t.Errorf("%s: Delay(%d) = %v, want %v", tt.name, tt.attempt, got, tt.want)
+		}
+	}
+
+	// Jitter stays within its fraction of the backoff.
+	p.Jitter = 0.5
+	for range 100 {
+		if got := p.Delay(2, io.ErrUnexpectedEOF); got < 15*time.Millisecond || got > 45*time.Millisecond {
+			t.Fatalf("jittered Delay(2) = %v, want within 15ms-45ms", got)
+		}
+	}
+}
+
+func TestRetryable(t *testing.T) {
+	tests := []struct {
+		err  error
+		want bool
+	}{
+		{nil, false},
+		{errors.New("bad request"), false},
+		{context.Canceled, false},
+		{fmt.Errorf("request: %w", context.DeadlineExceeded), true},
+		{io.ErrUnexpectedEOF, true},
+		{syscall.ECONNRESET, true},
+		{syscall.ECONNREFUSED, true},
+		{statusError(400), false},
+		{statusError(404), false},
+		{statusError(408), true},
+		{statusError(429), true},
+		{statusError(500), true},
+		{statusError(503), true},
+		{failure.Wrap(failure.LLMQuota, errors.New("quota")), true},
+		{failure.Wrap(failure.LLMAuth, statusError(503)), false},
+		{failure.Wrap(failure.LLMBlocked, io.ErrUnexpectedEOF), false},
+		{failure.Wrap(failure.Interrupted, io.ErrUnexpectedEOF), false},
+		{failure.Wrap(failure.LLM, statusError(502)), true},
+	}
+	for _, tt := range tests {
+		if got := Retryable(tt.err); got != tt.want {
+			t.Errorf("Retryable(%v) = %v, want %v", tt.err, got, tt.want)
+		}
+	}
+}
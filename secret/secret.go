@@ -0,0 +1,103 @@
+// Package secret keeps credentials such as API keys out of log lines and error messages.
+package secret
+
+import (
+	"bytes"
+	"io"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// Mask replaces every redacted secret.
+const Mask = "[REDACTED]"
+
+// MinLength is the shortest value Register accepts, so that placeholders such as "none" given
+// for servers without authentication are not masked everywhere.
+const MinLength = 8
+
+var (
+	mu      sync.RWMutex
+	secrets []string
+
+	// queryKey matches credentials passed as URL query parameters, e.g. ?key=... for Gemini.
+	queryKey = regexp.MustCompile(`([?&](?:key|api_key|apikey|access_token)=)[^&\s"']+`)
+)
+
+// Register adds value to the secrets removed by Redact. Values shorter than MinLength, after
+// trimming spaces, are ignored.
+func Register(value string) {
+	value = strings.TrimSpace(value)
+	if len(value) < MinLength {
+		return
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	for _, s := range secrets {
+		if s == value {
+			return
+		}
+	}
+	secrets = append(secrets, value)
+}
+
+// Redact replaces the registered secrets and credentials in URL query parameters in s with Mask.
+func Redact(s string) string {
+	mu.RLock()
+	for _, secret := range secrets {
+		s = strings.ReplaceAll(s, secret, Mask)
+	}
+	mu.RUnlock()
+	return queryKey.ReplaceAllString(s, "${1}"+Mask)
+}
+
+// redactedError is an error whose message has its secrets redacted.
+type redactedError struct {
+	err error
+}
+
+func (e *redactedError) Error() string {
+	return Redact(e.err.Error())
+}
+
+func (e *redactedError) Unwrap() error {
+	return e.err
+}
+
+// Error wraps err so that its message is redacted; errors.Is and errors.As still see err.
+func Error(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &redactedError{err}
+}
+
+// writer redacts everything written through it, a line at a time.
+type writer struct {
+	mu      sync.Mutex
+	w       io.Writer
+	pending []byte // the start of a line not yet terminated
+}
+
+func (w *writer) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.pending = append(w.pending, p...)
+	end := bytes.LastIndexByte(w.pending, '\n') + 1
+	if end == 0 {
+		return len(p), nil
+	}
+	_, err := io.WriteString(w.w, Redact(string(w.pending[:end])))
+	w.pending = append(w.pending[:0], w.pending[end:]...)
+	if err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// Writer returns a writer that redacts what is written to w, for use with log.SetOutput and
+// log.New. Output is passed on a line at a time, so a secret split across several writes is
+// still redacted; text after the last newline is held until the line is complete.
+func Writer(w io.Writer) io.Writer {
+	return &writer{w: w}
+}
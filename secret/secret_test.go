@@ -0,0 +1,92 @@
+package secret
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"strings"
+	"testing"
+)
+
+func TestRegister(t *testing.T) {
+	Register("  sk-live-0123456789  ")
+	Register("sk-live-0123456789") // duplicates are kept once
+	Register("none")               // shorter than MinLength
+	Register("1234567")
+	Register("")
+
+	if got, want := Redact("key sk-live-0123456789 and none, 1234567"), "key "+Mask+" and none, 1234567"; got != want {
+		t.Errorf("Redact = %q, want %q", got, want)
+	}
+	mu.RLock()
+	n := 0
+	for _, s := range secrets {
+		if s == "sk-live-0123456789" {
+			n++
+		}
+	}
+	mu.RUnlock()
+	if n != 1 {
+		t.Errorf("secret registered %d times, want once", n)
+	}
+}
+
+func TestRedact(t *testing.T) {
+	Register("AIzaSyExampleKey123")
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"no secrets here", "no secrets here"},
+		{"twice AIzaSyExampleKey123 AIzaSyExampleKey123", "twice " + Mask + " " + Mask},
+		{"GET https://example.com/v1/files?key=abc123&alt=json", "GET https://example.com/v1/files?key=" + Mask + "&alt=json"},
+		{`url "https://x.test/?alt=sse&api_key=zzz"`, `url "https://x.test/?alt=sse&api_key=` + Mask + `"`},
+		{"https://x.test/?access_token=tok more", "https://x.test/?access_token=" + Mask + " more"},
+		{"?monkey=banana", "?monkey=banana"},
+	}
+	for _, tt := range tests {
+		if got := Redact(tt.in); got != tt.want {
+			t.Errorf("Redact(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestError(t *testing.T) {
+	Register("sk-error-secret-42")
+	if Error(nil) != nil {
+		t.Error("Error(nil) != nil")
+	}
+	err := Error(fmt.Errorf("calling with sk-error-secret-42: %w", fs.ErrPermission))
+	if strings.Contains(err.Error(), "sk-error-secret-42") {
+		t.Errorf("error message %q contains the secret", err)
+	}
+	if !errors.Is(err, fs.ErrPermission) {
+		t.Error("redacted error does not unwrap")
+	}
+}
+
+func TestWriter(t *testing.T) {
+	Register("sk-writer-secret-99")
+	var b strings.Builder
+	w := Writer(&b)
+
+	// The key is split across two writes.
+	fmt.Fprint(w, "request failed: key sk-writer-")
+	if b.Len() != 0 {
+		t.Errorf("partial line written early: %q", b.String())
+	}
+	fmt.Fprint(w, "secret-99 rejected\nnext ")
+	fmt.Fprint(w, "line\n")
+	if want := "request failed: key " + Mask + " rejected\nnext line\n"; b.String() != want {
+		t.Errorf("Writer output = %q, want %q", b.String(), want)
+	}
+
+	// log.Logger writes whole lines.
+	b.Reset()
+	l := log.New(w, "", 0)
+	l.Printf("using %s", "sk-writer-secret-99")
+	if want := "using " + Mask + "\n"; b.String() != want {
+		t.Errorf("log output = %q, want %q", b.String(), want)
+	}
+}
@@ -0,0 +1,228 @@
+package subtitle
+
+import (
+	"math"
+	"strings"
+	"time"
+
+	"github.com/utkarsh-cpu/videoSummaryGo/audio"
+)
+
+// Defaults used when the corresponding Options field is zero.
+const (
+	DefaultMaxLineLength  = 42
+	DefaultMaxLines       = 2
+	DefaultMaxCueDuration = 7 * time.Second
+)
+
+// boundaryTolerance is how close a segment must be to a chunk boundary to count as touching it.
+const boundaryTolerance = time.Second
+
+// Options control how segments are split into cues.
+type Options struct {
+	MaxLineLength  int           // characters per line
+	MaxLines       int           // lines per cue
+	MaxCueDuration time.Duration // longer segments are split into several cues
+}
+
+// withDefaults fills zero fields with the package defaults.
+func (o Options) withDefaults() Options {
+	if o.MaxLineLength <= 0 {
+		o.MaxLineLength = DefaultMaxLineLength
+	}
+	if o.MaxLines <= 0 {
+		o.MaxLines = DefaultMaxLines
+	}
+	if o.MaxCueDuration <= 0 {
+		o.MaxCueDuration = DefaultMaxCueDuration
+	}
+	return o
+}
+
+// Chunk is the audio of one video chunk. Start and End are the chunk's position in the video
+// and Segments carry absolute video timestamps.
+type Chunk struct {
+	Start    time.Duration
+	End      time.Duration
+	Segments []audio.Segment
+}
+
+// Cue is one subtitle entry.
+type Cue struct {
+	Start time.Duration
+	End   time.Duration
+	Lines []string
+}
+
+// BuildCues turns the segments of consecutive chunks into non-overlapping cues.
+//
+// Segments are clamped to their chunk, because whisper may report an end time past the end
+// of the audio it was given. A sentence cut by a chunk boundary shows up as a last segment
+// ending at the boundary without terminal punctuation followed by a first segment starting at
+// the boundary; those two are joined before splitting, so the cue does not break mid-sentence.
+func BuildCues(chunks []Chunk, opts Options) []Cue {
+	opts = opts.withDefaults()
+
+	var segments []audio.Segment
+	for i, c := range chunks {
+		clamped := clampSegments(c)
+		if len(clamped) == 0 {
+			continue
+		}
+		if i > 0 && len(segments) > 0 {
+			last := &segments[len(segments)-1]
+			first := clamped[0]
+			if continuesAcross(*last, first, c.Start) {
+				last.Text = strings.TrimSpace(last.Text + " " + first.Text)
+				last.End = first.End
+				clamped = clamped[1:]
+			}
+		}
+		segments = append(segments, clamped...)
+	}
+
+	var cues []Cue
+	var prevEnd time.Duration
+	for _, s := range segments {
+		if s.Start < prevEnd {
+			s.Start = prevEnd
+		}
+		if s.End <= s.Start {
+			continue
+		}
+		cues = append(cues, splitSegment(s, opts)...)
+		prevEnd = s.End
+	}
+	return cues
+}
+
+// clampSegments drops empty segments and limits the rest to the chunk's time range.
+func clampSegments(c Chunk) []audio.Segment {
+	var out []audio.Segment
+	for _, s := range c.Segments {
+		s.Text = strings.TrimSpace(s.Text)
+		if s.Text == "" || isNonSpeech(s.Text) {
+			continue
+		}
+		if s.Start < c.Start {
+			s.Start = c.Start
+		}
+		if c.End > c.Start && s.End > c.End {
+			s.End = c.End
+		}
+		if s.End <= s.Start {
+			continue
+		}
+		out = append(out, s)
+	}
+	return out
+}
+
+// isNonSpeech reports whether text is a whisper annotation such as "[BLANK_AUDIO]" or "(music)".
+func isNonSpeech(text string) bool {
+	return (strings.HasPrefix(text, "[") && strings.HasSuffix(text, "]")) ||
+		(strings.HasPrefix(text, "(") && strings.HasSuffix(text, ")"))
+}
+
+// continuesAcross reports whether next continues the sentence of last across the chunk boundary.
+func continuesAcross(last audio.Segment, next audio.Segment, boundary time.Duration) bool {
+	if boundary-last.End > boundaryTolerance || next.Start-boundary > boundaryTolerance {
+		return false
+	}
+	return !strings.HasSuffix(last.Text, ".") && !strings.HasSuffix(last.Text, "?") && !strings.HasSuffix(last.Text, "!")
+}
+
+// splitSegment divides a segment into cues that respect the line and duration limits.
+// Time is shared between cues in proportion to their text length.
+func splitSegment(s audio.Segment, opts Options) []Cue {
+	words := strings.Fields(s.Text)
+	maxChars := opts.MaxLineLength * opts.MaxLines
+	pieces := packWords(words, maxChars)
+
+	byDuration := int(math.Ceil(float64(s.End-s.Start) / float64(opts.MaxCueDuration)))
+	if byDuration > len(pieces) && byDuration <= len(words) {
+		pieces = splitEvenly(words, byDuration)
+	}
+
+	total := 0
+	for _, p := range pieces {
+		total += len(p)
+	}
+	cues := make([]Cue, 0, len(pieces))
+	start := s.Start
+	consumed := 0
+	for i, p := range pieces {
+		consumed += len(p)
+		end := s.Start + time.Duration(float64(s.End-s.Start)*float64(consumed)/float64(total))
+		if i == len(pieces)-1 {
+			end = s.End
+		}
+		cues = append(cues, Cue{Start: start, End: end, Lines: wrap(p, opts.MaxLineLength)})
+		start = end
+	}
+	return cues
+}
+
+// packWords greedily groups words into pieces of at most maxChars characters.
+func packWords(words []string, maxChars int) []string {
+	var pieces []string
+	var current string
+	for _, w := range words {
+		if current != "" && len(current)+1+len(w) > maxChars {
+			pieces = append(pieces, current)
+			current = ""
+		}
+		if current == "" {
+			current = w
+		} else {
+			current += " " + w
+		}
+	}
+	if current != "" {
+		pieces = append(pieces, current)
+	}
+	return pieces
+}
+
+// splitEvenly groups words into n pieces of roughly equal character length.
+func splitEvenly(words []string, n int) []string {
+	total := len(strings.Join(words, " "))
+	pieces := make([]string, 0, n)
+	var current []string
+	length := 0
+	for i, w := range words {
+		current = append(current, w)
+		length += len(w) + 1
+		remainingWords := len(words) - i - 1
+		remainingPieces := n - len(pieces) - 1
+		if remainingPieces > 0 && (length >= total*(len(pieces)+1)/n || remainingWords == remainingPieces) {
+			pieces = append(pieces, strings.Join(current, " "))
+			current = nil
+		}
+	}
+	if len(current) > 0 {
+		pieces = append(pieces, strings.Join(current, " "))
+	}
+	return pieces
+}
+
+// wrap breaks text into lines of at most width characters, never splitting words.
+func wrap(text string, width int) []string {
+	var lines []string
+	var line string
+	for _, w := range strings.Fields(text) {
+		if line != "" && len(line)+1+len(w) > width {
+			lines = append(lines, line)
+			line = ""
+		}
+		if line == "" {
+			line = w
+		} else {
+			line += " " + w
+		}
+	}
+	if line != "" {
+		lines = append(lines, line)
+	}
+	return lines
+}
@@ -0,0 +1,137 @@
+package subtitle
+
+import (
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/utkarsh-cpu/videoSummaryGo/audio"
+)
+
+func seg(start, end float64, text string) audio.Segment {
+	return audio.Segment{Start: secs(start), End: secs(end), Text: text}
+}
+
+func secs(s float64) time.Duration {
+	return time.Duration(s * float64(time.Second))
+}
+
+func TestBuildCues(t *testing.T) {
+	tests := []struct {
+		name   string
+		chunks []Chunk
+		want   []Cue
+	}{
+		{
+			name: "clamps, joins across the boundary and removes overlaps",
+			chunks: []Chunk{
+				{Start: 0, End: secs(60), Segments: []audio.Segment{
+					seg(0, 3, " Hello everyone. "),
+					seg(3, 4, "[BLANK_AUDIO]"),
+					seg(4, 5, "(music)"),
+					seg(58, 61.5, "and the next"), // whisper ran past the end of the chunk
+				}},
+				{Start: secs(60), End: secs(120), Segments: []audio.Segment{
+					seg(60.2, 63, "topic is sorting."),
+					seg(62, 65, "Overlap."),
+					seg(119, 125, "Bye."),
+				}},
+			},
+			want: []Cue{
+				{Start: 0, End: secs(3), Lines: []string{"Hello everyone."}},
+				{Start: secs(58), End: secs(63), Lines: []string{"and the next topic is sorting."}},
+				{Start: secs(63), End: secs(65), Lines: []string{"Overlap."}},
+				{Start: secs(119), End: secs(120), Lines: []string{"Bye."}},
+			},
+		},
+		{
+			name: "finished sentences are not joined",
+			chunks: []Chunk{
+				{Start: 0, End: secs(60), Segments: []audio.Segment{seg(55, 60, "Done?")}},
+				{Start: secs(60), End: secs(120), Segments: []audio.Segment{seg(60, 62, "Yes.")}},
+			},
+			want: []Cue{
+				{Start: secs(55), End: secs(60), Lines: []string{"Done?"}},
+				{Start: secs(60), End: secs(62), Lines: []string{"Yes."}},
+			},
+		},
+		{
+			name: "segments far from the boundary are not joined",
+			chunks: []Chunk{
+				{Start: 0, End: secs(60), Segments: []audio.Segment{seg(50, 57, "trailing off")}},
+				{Start: secs(60), End: secs(120), Segments: []audio.Segment{seg(62, 64, "new thought")}},
+			},
+			want: []Cue{
+				{Start: secs(50), End: secs(57), Lines: []string{"trailing off"}},
+				{Start: secs(62), End: secs(64), Lines: []string{"new thought"}},
+			},
+		},
+		{
+			name: "segments outside their chunk are dropped",
+			chunks: []Chunk{
+				{Start: secs(60), End: secs(120), Segments: []audio.Segment{seg(10, 20, "stale"), seg(120, 121, "late"), seg(70, 70, "empty")}},
+			},
+			want: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := BuildCues(tt.chunks, Options{})
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("BuildCues() =\n%v\nwant\n%v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSplitSegmentByLength(t *testing.T) {
+	opts := Options{MaxLineLength: 10, MaxLines: 1, MaxCueDuration: time.Minute}
+	got := splitSegment(seg(0, 12, "one two three four five six"), opts)
+	// Time is shared in proportion to the characters of each piece: 7, 10 and 8 of 25.
+	want := []Cue{
+		{Start: 0, End: secs(3.36), Lines: []string{"one two"}},
+		{Start: secs(3.36), End: secs(8.16), Lines: []string{"three four"}},
+		{Start: secs(8.16), End: secs(12), Lines: []string{"five six"}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("splitSegment() =\n%v\nwant\n%v", got, want)
+	}
+}
+
+func TestSplitSegmentWrapsLines(t *testing.T) {
+	opts := Options{MaxLineLength: 10, MaxLines: 2}
+	got := splitSegment(seg(0, 2, "one two three four"), opts)
+	want := []Cue{{Start: 0, End: secs(2), Lines: []string{"one two", "three four"}}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("splitSegment() = %v, want %v", got, want)
+	}
+}
+
+func TestSplitSegmentByDuration(t *testing.T) {
+	got := splitSegment(seg(0, 20, "a b c d e f"), Options{}.withDefaults())
+	var texts []string
+	for _, c := range got {
+		texts = append(texts, strings.Join(c.Lines, " "))
+	}
+	if want := []string{"a b", "c d", "e f"}; !slices.Equal(texts, want) {
+		t.Fatalf("cue texts = %q, want %q", texts, want)
+	}
+	if got[0].Start != 0 || got[len(got)-1].End != secs(20) {
+		t.Errorf("cues span %s to %s, want 0s to 20s", got[0].Start, got[len(got)-1].End)
+	}
+	for i, c := range got {
+		if c.End-c.Start > DefaultMaxCueDuration {
+			t.Errorf("cue %d lasts %s, longer than %s", i, c.End-c.Start, DefaultMaxCueDuration)
+		}
+		if i > 0 && c.Start != got[i-1].End {
+			t.Errorf("cue %d starts at %s, previous ends at %s", i, c.Start, got[i-1].End)
+		}
+	}
+
+	// A single word cannot be split, however long it is spoken.
+	if got := splitSegment(seg(0, 20, "Hmmmm."), Options{}.withDefaults()); len(got) != 1 {
+		t.Errorf("splitSegment of one word gave %d cues, want 1", len(got))
+	}
+}
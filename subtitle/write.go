@@ -0,0 +1,60 @@
+package subtitle
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// Format is a subtitle file format.
+type Format string
+
+// Supported formats.
+const (
+	SRT Format = "srt"
+	VTT Format = "vtt"
+)
+
+// Write writes cues to w in format.
+func Write(w io.Writer, format Format, cues []Cue) error {
+	switch format {
+	case SRT:
+		return WriteSRT(w, cues)
+	case VTT:
+		return WriteVTT(w, cues)
+	default:
+		return fmt.Errorf("unknown subtitle format %q", format)
+	}
+}
+
+// WriteSRT writes cues as SubRip.
+func WriteSRT(w io.Writer, cues []Cue) error {
+	bw := bufio.NewWriter(w)
+	for i, c := range cues {
+		fmt.Fprintf(bw, "%d\n%s --> %s\n%s\n\n", i+1, timestamp(c.Start, ","), timestamp(c.End, ","), strings.Join(c.Lines, "\n"))
+	}
+	return bw.Flush()
+}
+
+// WriteVTT writes cues as WebVTT.
+func WriteVTT(w io.Writer, cues []Cue) error {
+	bw := bufio.NewWriter(w)
+	bw.WriteString("WEBVTT\n\n")
+	for _, c := range cues {
+		text := vttEscaper.Replace(strings.Join(c.Lines, "\n"))
+		fmt.Fprintf(bw, "%s --> %s\n%s\n\n", timestamp(c.Start, "."), timestamp(c.End, "."), text)
+	}
+	return bw.Flush()
+}
+
+// vttEscaper escapes the characters WebVTT cue text reserves for tags and character references;
+// escaping ">" also keeps "-->" out of the text.
+var vttEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+// timestamp renders d as hh:mm:ss<sep>mmm.
+func timestamp(d time.Duration, sep string) string {
+	ms := d.Milliseconds()
+	return fmt.Sprintf("%02d:%02d:%02d%s%03d", ms/3600000, ms/60000%60, ms/1000%60, sep, ms%1000)
+}
@@ -0,0 +1,66 @@
+package subtitle
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTimestamp(t *testing.T) {
+	tests := []struct {
+		d       time.Duration
+		srt     string
+		vtt     string
+		comment string
+	}{
+		{0, "00:00:00,000", "00:00:00.000", "zero"},
+		{1500 * time.Millisecond, "00:00:01,500", "00:00:01.500", "milliseconds"},
+		{time.Hour + 2*time.Minute + 3*time.Second + 45*time.Millisecond, "01:02:03,045", "01:02:03.045", "every field"},
+		{59*time.Minute + 59*time.Second + 999999*time.Microsecond, "00:59:59,999", "00:59:59.999", "truncated, not rounded"},
+		{100 * time.Hour, "100:00:00,000", "100:00:00.000", "more than 99 hours"},
+	}
+	for _, tt := range tests {
+		if got := timestamp(tt.d, ","); got != tt.srt {
+			t.Errorf("%s: SRT timestamp(%s) = %q, want %q", tt.comment, tt.d, got, tt.srt)
+		}
+		if got := timestamp(tt.d, "."); got != tt.vtt {
+			t.Errorf("%s: VTT timestamp(%s) = %q, want %q", tt.comment, tt.d, got, tt.vtt)
+		}
+	}
+}
+
+var testCues = []Cue{
+	{Start: 0, End: 2500 * time.Millisecond, Lines: []string{"Welcome to the lecture."}},
+	{Start: 61 * time.Second, End: 64*time.Second + 20*time.Millisecond, Lines: []string{"If x < y & y > z,", "then --> x < z."}},
+}
+
+func TestWriteSRT(t *testing.T) {
+	var b strings.Builder
+	if err := Write(&b, SRT, testCues); err != nil {
+		t.Fatal(err)
+	}
+	want := "1\n00:00:00,000 --> 00:00:02,500\nWelcome to the lecture.\n\n" +
+		"2\n00:01:01,000 --> 00:01:04,020\nIf x < y & y > z,\nthen --> x < z.\n\n"
+	if b.String() != want {
+		t.Errorf("SRT output:\n%s\nwant:\n%s", b.String(), want)
+	}
+}
+
+func TestWriteVTT(t *testing.T) {
+	var b strings.Builder
+	if err := Write(&b, VTT, testCues); err != nil {
+		t.Fatal(err)
+	}
+	want := "WEBVTT\n\n" +
+		"00:00:00.000 --> 00:00:02.500\nWelcome to the lecture.\n\n" +
+		"00:01:01.000 --> 00:01:04.020\nIf x &lt; y &amp; y &gt; z,\nthen --&gt; x &lt; z.\n\n"
+	if b.String() != want {
+		t.Errorf("VTT output:\n%s\nwant:\n%s", b.String(), want)
+	}
+}
+
+func TestWriteUnknownFormat(t *testing.T) {
+	if err := Write(&strings.Builder{}, Format("ass"), testCues); err == nil {
+		t.Error("Write with an unknown format succeeded")
+	}
+}
@@ -0,0 +1,36 @@
+package summarize
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/utkarsh-cpu/videoSummaryGo/llm"
+)
+
+// DefaultInstructions is the task given to the LLM ahead of the transcripts when no other is set.
+const DefaultInstructions = `Here is a raw transcription of a video. Your task is to refine it into a well-structured, human-like summary with explanations while keeping all the original details. Analyze the lecture provided in the audio transcription and video text.  Identify the main topic, key arguments, supporting evidence, and any examples used.  Explain the lecture in a structured way, highlighting the connections between different ideas.  Use information from both the audio transcription and video text to create a comprehensive explanation, also use timestamp to help us correlate with the audio transcript:`
+
+// BuildPrompt returns the combined summary prompt for the raw audio and video-text transcripts.
+// Empty instructions mean DefaultInstructions.
+func BuildPrompt(instructions string, audioTranscript string, videoTranscript string) string {
+	if instructions == "" {
+		instructions = DefaultInstructions
+	}
+	return fmt.Sprintf(`%s
+
+    --- RAW TRANSCRIPTION of Audio ---
+    %s
+
+    --- RAW TRANSCRIPTION of Video Text ---
+    %s
+
+    Please rewrite it clearly with explanations where needed, ensuring it's easy to read and understand.`, instructions, audioTranscript, videoTranscript)
+}
+
+// Summarize sends the combined prompt to model and returns the summary text.
+func Summarize(ctx context.Context, model llm.LLM, instructions string, audioTranscript string, videoTranscript string, videoIndex int) (string, error) {
+	combinedPrompt := []llm.Part{
+		llm.Text(BuildPrompt(instructions, audioTranscript, videoTranscript)),
+	}
+	return llm.SendPrompt(ctx, model, combinedPrompt, nil, videoIndex)
+}
@@ -0,0 +1,57 @@
+package visual
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/utkarsh-cpu/videoSummaryGo/progress"
+)
+
+// FallbackChain tries each transcriber in order and returns the first successful result.
+type FallbackChain struct {
+	Transcribers []VisualTranscriber
+}
+
+// NewFallbackChain returns a FallbackChain over transcribers.
+func NewFallbackChain(transcribers ...VisualTranscriber) *FallbackChain {
+	return &FallbackChain{Transcribers: transcribers}
+}
+
+// Name lists the chained backends.
+func (c *FallbackChain) Name() string {
+	name := "fallback("
+	for i, t := range c.Transcribers {
+		if i > 0 {
+			name += ","
+		}
+		name += t.Name()
+	}
+	return name + ")"
+}
+
+// Transcribe returns the result of the first transcriber that succeeds. Result.Backend names that
+// transcriber. If all of them fail the returned error joins every failure.
+func (c *FallbackChain) Transcribe(ctx context.Context, videoPath string, opts Options) (*Result, error) {
+	var errs []error
+	for i, t := range c.Transcribers {
+		result, err := t.Transcribe(ctx, videoPath, opts)
+		if err == nil {
+			if result.Backend == "" {
+				result.Backend = t.Name()
+			}
+			return result, nil
+		}
+		errs = append(errs, fmt.Errorf("%s: %w", t.Name(), err))
+		if ctx.Err() != nil {
+			break // cancelled: don't start the next transcriber
+		}
+		if i+1 < len(c.Transcribers) {
+			progress.Printf(ctx, "Chunk %d for video %d: %s failed, falling back to %s...", opts.ChunkNum, opts.VideoIndex, t.Name(), c.Transcribers[i+1].Name())
+		}
+	}
+	if len(errs) == 0 {
+		return nil, errors.New("no visual transcribers configured")
+	}
+	return nil, errors.Join(errs...)
+}
@@ -0,0 +1,78 @@
+package visual
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/utkarsh-cpu/videoSummaryGo/failure"
+	"github.com/utkarsh-cpu/videoSummaryGo/llm"
+	"github.com/utkarsh-cpu/videoSummaryGo/media"
+)
+
+// DefaultFramesPrompt is the instruction sent ahead of the extracted frames.
+const DefaultFramesPrompt = "## Task Description\nThese images are frames taken one second apart from a video. Provide a detailed raw transcription of text displayed in them."
+
+// defaultMaxFrames caps the number of frames sent per chunk when LLMFramesTranscriber.MaxFrames is zero.
+const defaultMaxFrames = 8
+
+// LLMFramesTranscriber extracts frames and sends them inline as images to a vision model.
+// It suits backends that cannot take video uploads.
+type LLMFramesTranscriber struct {
+	LLM       llm.LLM
+	MaxFrames int    // frames are sampled evenly when a chunk has more than this
+	Prompt    string // default DefaultFramesPrompt
+}
+
+// Name returns the backend name with a "-frames" suffix, e.g. "openai-frames".
+func (t *LLMFramesTranscriber) Name() string {
+	return t.LLM.Name() + "-frames"
+}
+
+// Transcribe extracts frames from videoPath and asks the model for the text they show.
+func (t *LLMFramesTranscriber) Transcribe(ctx context.Context, videoPath string, opts Options) (*Result, error) {
+	framePaths, err := media.ExtractFrames(ctx, videoPath, opts.VideoIndex, opts.ChunkNum)
+	if err != nil {
+		return nil, fmt.Errorf("error extracting frames for video %d chunk %d: %w", opts.VideoIndex, opts.ChunkNum, err)
+	}
+	defer media.RemoveFrames(framePaths)
+
+	maxFrames := t.MaxFrames
+	if maxFrames <= 0 {
+		maxFrames = defaultMaxFrames
+	}
+	instructions := t.Prompt
+	if instructions == "" {
+		instructions = DefaultFramesPrompt
+	}
+	prompt := []llm.Part{llm.Text(instructions)}
+	for _, fp := range sampleFrames(framePaths, maxFrames) {
+		data, err := os.ReadFile(fp)
+		if err != nil {
+			return nil, fmt.Errorf("error reading frame %s: %w", fp, err)
+		}
+		prompt = append(prompt, llm.Image("image/jpeg", data))
+	}
+
+	transcript, err := llm.SendPrompt(ctx, t.LLM, prompt, nil, opts.VideoIndex)
+	if err != nil {
+		return nil, err
+	}
+	if transcript == "" {
+		return nil, failure.Wrap(failure.LLM, errors.New("LLM returned no transcription"))
+	}
+	return &Result{Text: transcript, Backend: t.Name()}, nil
+}
+
+// sampleFrames returns at most n evenly spaced paths.
+func sampleFrames(paths []string, n int) []string {
+	if len(paths) <= n {
+		return paths
+	}
+	sampled := make([]string, 0, n)
+	for i := 0; i < n; i++ {
+		sampled = append(sampled, paths[i*len(paths)/n])
+	}
+	return sampled
+}
@@ -0,0 +1,56 @@
+package visual
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/utkarsh-cpu/videoSummaryGo/failure"
+	"github.com/utkarsh-cpu/videoSummaryGo/llm"
+	"github.com/utkarsh-cpu/videoSummaryGo/progress"
+)
+
+// DefaultVideoPrompt is the instruction sent with an uploaded video chunk.
+const DefaultVideoPrompt = "## Task Description\nAnalyze the video and provide a detailed raw transcription of text displayed in the video."
+
+// LLMVideoTranscriber uploads the video to a multimodal LLM and asks it for the on-screen text.
+type LLMVideoTranscriber struct {
+	LLM    llm.LLM
+	Prompt string // default DefaultVideoPrompt
+}
+
+// Name returns the backend name with a "-video" suffix, e.g. "gemini-video".
+func (t *LLMVideoTranscriber) Name() string {
+	return t.LLM.Name() + "-video"
+}
+
+// Transcribe uploads videoPath, prompts the model with it once the upload is active and deletes the upload afterwards.
+func (t *LLMVideoTranscriber) Transcribe(ctx context.Context, videoPath string, opts Options) (*Result, error) {
+	uploadedFile, err := t.LLM.UploadMedia(ctx, videoPath)
+	if err != nil {
+		return nil, failure.Wrap(failure.Upload, fmt.Errorf("error uploading video chunk: %w", err))
+	}
+	// Delete the upload even when ctx is cancelled so interrupted runs leave no remote files behind.
+	defer func() { t.LLM.DeleteMedia(context.WithoutCancel(ctx), uploadedFile) }()
+
+	progress.Printf(ctx, "Chunk %d for video %d: Video chunk uploaded as: %s", opts.ChunkNum, opts.VideoIndex, uploadedFile.URI)
+
+	prompt := t.Prompt
+	if prompt == "" {
+		prompt = DefaultVideoPrompt
+	}
+	promptList := []llm.Part{
+		llm.Text(prompt),
+		llm.MediaPart(uploadedFile),
+	}
+	videoTranscript, err := llm.SendPrompt(ctx, t.LLM, promptList, nil, opts.VideoIndex)
+	if err != nil {
+		return nil, err
+	}
+	if videoTranscript == "" {
+		return nil, failure.Wrap(failure.LLM, errors.New("LLM returned no transcription"))
+	}
+
+	progress.Printf(ctx, "Chunk %d for video %d: Video transcribed by LLM.", opts.ChunkNum, opts.VideoIndex)
+	return &Result{Text: videoTranscript, Backend: t.Name()}, nil
+}
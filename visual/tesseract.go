@@ -0,0 +1,162 @@
+package visual
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"sync"
+
+	"github.com/utkarsh-cpu/videoSummaryGo/failure"
+	"github.com/utkarsh-cpu/videoSummaryGo/media"
+	"github.com/utkarsh-cpu/videoSummaryGo/progress"
+)
+
+// TesseractFramesTranscriber extracts one frame per second and OCRs each frame with tesseract.
+type TesseractFramesTranscriber struct {
+	Language string // tesseract language packs, e.g. "eng+deu" (default: tesseract's own, eng)
+}
+
+// Name returns "tesseract-frames".
+func (t *TesseractFramesTranscriber) Name() string {
+	return "tesseract-frames"
+}
+
+// Transcribe extracts frames from videoPath, OCRs them and removes the frames afterwards.
+func (t *TesseractFramesTranscriber) Transcribe(ctx context.Context, videoPath string, opts Options) (*Result, error) {
+	framePaths, err := media.ExtractFrames(ctx, videoPath, opts.VideoIndex, opts.ChunkNum)
+	if err != nil {
+		return nil, fmt.Errorf("error extracting frames for video %d chunk %d: %w", opts.VideoIndex, opts.ChunkNum, err)
+	}
+	transcript, err := TranscribeFramesTesseract(ctx, framePaths, t.Language)
+	// Cleanup extracted frames.
+	media.RemoveFrames(framePaths)
+	if err != nil {
+		return nil, fmt.Errorf("error transcribing frames with Tesseract for video %d chunk %d: %w", opts.VideoIndex, opts.ChunkNum, err)
+	}
+	return &Result{Text: transcript, Backend: t.Name()}, nil
+}
+
+// frameResult is the OCR outcome for a single frame.
+type frameResult struct {
+	Text  string
+	Error error
+}
+
+// TranscribeFramesTesseract runs tesseract over every frame and concatenates the recognised text.
+// language selects tesseract's language packs ("" for its default). No new frames are started
+// once ctx is cancelled.
+func TranscribeFramesTesseract(ctx context.Context, framePaths []string, language string) (string, error) {
+	var combinedTranscript strings.Builder
+	var wg sync.WaitGroup
+	frameResults := make(chan frameResult, len(framePaths)) // Buffered channel for results
+
+	// Limit concurrency to the number of CPUs (or a reasonable limit)
+	numWorkers := runtime.NumCPU()
+	if numWorkers > 8 { //  cap it to 8 for now to avoid too many subprocesses
+		numWorkers = 8
+	}
+	guard := make(chan struct{}, numWorkers) // Semaphore
+
+	for _, framePath := range framePaths {
+		if ctx.Err() != nil {
+			break
+		}
+		wg.Add(1)
+		guard <- struct{}{} // Acquire a slot
+
+		go func(fp string) {
+			defer wg.Done()
+			defer func() { <-guard }() // Release the slot
+			frameResults <- ocrFrame(ctx, fp, language)
+		}(framePath)
+	}
+
+	wg.Wait()           // Wait for all goroutines to finish
+	close(frameResults) // Close the channel - no more results coming
+
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+
+	// Collect results from the channel
+	var firstErr error
+	succeeded := 0
+	for result := range frameResults {
+		if result.Error != nil {
+			progress.Printf(ctx, "Warning: %v", result.Error)
+			if firstErr == nil {
+				firstErr = result.Error
+			}
+			continue // Skip frames with errors
+		}
+		succeeded++
+		combinedTranscript.WriteString(result.Text)
+		combinedTranscript.WriteString("\n")
+	}
+
+	if succeeded == 0 && firstErr != nil {
+		return "", firstErr // every frame failed, e.g. tesseract is not installed
+	}
+	return combinedTranscript.String(), nil
+}
+
+// ocrFrame re-encodes one frame as JPEG and runs tesseract on it.
+func ocrFrame(ctx context.Context, fp string, language string) frameResult {
+	// Open the image file
+	imgFile, err := os.Open(fp)
+	if err != nil {
+		return frameResult{"", fmt.Errorf("error opening image file %s: %w", fp, err)}
+	}
+
+	// Decode the image
+	img, _, err := image.Decode(imgFile)
+	imgFile.Close() // Close immediately after decoding
+	if err != nil {
+		return frameResult{"", fmt.Errorf("error decoding image file %s: %w", fp, err)}
+	}
+
+	// Convert to JPEG
+	buf := new(bytes.Buffer)
+	if err := jpeg.Encode(buf, img, &jpeg.Options{Quality: 90}); err != nil {
+		return frameResult{"", fmt.Errorf("error encoding image to JPEG: %w", err)}
+	}
+	jpegBytes := buf.Bytes()
+
+	tempFile, err := os.CreateTemp("", "ocr_*.jpg")
+	if err != nil {
+		return frameResult{"", fmt.Errorf("error creating temp file: %w", err)}
+	}
+	tempFilePath := tempFile.Name()
+	defer os.Remove(tempFilePath)
+
+	_, err = tempFile.Write(jpegBytes)
+	if err != nil {
+		tempFile.Close() // Close before removing
+		return frameResult{"", fmt.Errorf("error writing to temp file: %w", err)}
+	}
+	if err := tempFile.Close(); err != nil {
+		return frameResult{"", fmt.Errorf("error closing temp file: %w", err)}
+	}
+
+	args := []string{tempFilePath, "stdout"}
+	if language != "" {
+		args = append(args, "-l", language)
+	}
+	cmd := exec.CommandContext(ctx, "tesseract", args...)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	err = cmd.Run()
+	if err != nil {
+		return frameResult{"", failure.Command(failure.OCR, fmt.Errorf("error running tesseract on %s: %w, stderr: %s", fp, err, stderr.String()))}
+	}
+
+	return frameResult{stdout.String(), nil}
+}
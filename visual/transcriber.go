@@ -0,0 +1,22 @@
+package visual
+
+import "context"
+
+// Options are per-call visual transcription settings.
+type Options struct {
+	VideoIndex int // only used for logs and temp dir names
+	ChunkNum   int // only used for logs and temp dir names
+}
+
+// Result is the text found in a video chunk and the backend that produced it.
+type Result struct {
+	Text    string
+	Backend string
+}
+
+// VisualTranscriber extracts the text displayed in a video file.
+type VisualTranscriber interface {
+	// Name identifies the backend in results and logs.
+	Name() string
+	Transcribe(ctx context.Context, videoPath string, opts Options) (*Result, error)
+}